@@ -1,16 +1,44 @@
 package main
 
 import (
+	"flag"
+	"fmt"
+	"os"
+
 	"github.com/EngoEngine/engo"
 
 	"github.com/SkeleboyStudios/SkeleDoom/scenes"
+	"github.com/SkeleboyStudios/SkeleDoom/systems"
 )
 
 func main() {
+	record := flag.String("record", "", "record the session as a demo to `file`")
+	play := flag.String("play", "", "play back the demo in `file`")
+	headless := flag.Bool("headless", false, "run without a window; with -play, verify the demo and exit")
+	speed := flag.Float64("speed", 1, "simulation speed multiplier, e.g. 8 to fast-forward a demo")
+	survival := flag.Bool("survival", false, "play survival mode: fight waves of enemies for a high score")
+	flag.Parse()
+
+	scene := &scenes.StartScene{Record: *record, Speed: float32(*speed), Survival: *survival}
+	if *play != "" {
+		demo, err := systems.LoadDemo(*play)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "could not load demo:", err)
+			os.Exit(1)
+		}
+		scene.Play = demo
+		scene.ExitAfterPlay = *headless
+	}
+
 	engo.Run(engo.RunOptions{
 		Title:         "Skeleboy Studios",
 		Width:         640,
 		Height:        360,
 		ScaleOnResize: true,
-	}, &scenes.StartScene{})
+		HeadlessMode:  *headless,
+	}, scene)
+
+	if scene.DemoMismatch {
+		os.Exit(1)
+	}
 }
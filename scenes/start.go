@@ -1,7 +1,10 @@
 package scenes
 
 import (
+	_ "embed"
+	"fmt"
 	"image/color"
+	"time"
 
 	"github.com/EngoEngine/ecs"
 	"github.com/EngoEngine/engo"
@@ -13,25 +16,74 @@ import (
 
 const StartSceneTypeString = "Start Scene"
 
-type StartScene struct{}
+// survivalLevel names the level in survival mode, for demos and saves.
+const survivalLevel = StartSceneTypeString + " (survival)"
+
+// startWaves is the level's survival mode.
+//
+//go:embed waves/start.json
+var startWaves []byte
+
+type StartScene struct {
+	// Record, if set, is the file the session is saved to as a demo when
+	// the game exits.
+	Record string
+	// Play, if set, is a demo played back in place of the player's input.
+	Play *systems.Demo
+	// Speed fast-forwards the simulation; see TickSystem.Speed.
+	Speed float32
+	// ExitAfterPlay quits the game once Play has finished, e.g. when
+	// verifying a demo headless.
+	ExitAfterPlay bool
+	// DemoMismatch is set if Play finished with the player in a different
+	// state from the recording.
+	DemoMismatch bool
+	// SavePath is the quicksave file. It defaults to quicksave.sav.
+	SavePath string
+	// ControlsPath is the controls config file. It defaults to
+	// systems.ControlsConfigPath().
+	ControlsPath string
+	// Survival plays the level in survival mode: waves of enemies from its
+	// spawners instead of the enemies placed in it. Playing a demo sets it
+	// to match the recording.
+	Survival bool
+
+	controls  *systems.ControlsConfig
+	demo      *systems.Demo
+	load      *systems.SaveGame // restored at the end of the next Setup
+	preloaded bool              // Preload has run; it isn't repeated when loading
+}
 
 func (s *StartScene) Type() string { return StartSceneTypeString }
 
+// level names the level being played, telling survival mode apart.
+func (s *StartScene) level() string {
+	if s.Survival {
+		return survivalLevel
+	}
+	return StartSceneTypeString
+}
+
 func (s *StartScene) Preload() {
+	// Quickloading sets the scene up again, but shaders and buttons are
+	// global and already registered.
+	if s.preloaded {
+		return
+	}
+	s.preloaded = true
 	engo.Files.Load("ui/statsborder.png")
 	engo.Files.Load("ui/bomb.png")
 	engo.Files.Load("ui/guns/pistol.png")
 	common.AddShader(shaders.ViewShader)
 	common.AddShader(shaders.MinimapShader)
-	engo.Input.RegisterButton("up", engo.KeyW, engo.KeyArrowUp)
-	engo.Input.RegisterButton("down", engo.KeyS, engo.KeyArrowDown)
-	engo.Input.RegisterButton("left", engo.KeyA, engo.KeyArrowLeft)
-	engo.Input.RegisterButton("right", engo.KeyD, engo.KeyArrowRight)
-	engo.Input.RegisterButton("sprint", engo.KeyLeftShift, engo.KeyRightShift)
-	engo.Input.RegisterButton("crouch", engo.KeyLeftControl, engo.KeyRightControl)
-	engo.Input.RegisterButton("jump", engo.KeySpace)
-	engo.Input.RegisterButton("reload", engo.KeyR)
-	engo.Input.RegisterButton("use", engo.KeyE)
+	if s.ControlsPath == "" {
+		s.ControlsPath = systems.ControlsConfigPath()
+	}
+	var err error
+	if s.controls, err = systems.LoadControls(s.ControlsPath); err != nil {
+		println("Warning: using the default controls:", err.Error())
+	}
+	s.controls.Apply()
 	engo.Input.RegisterAxis("hori", engo.NewAxisMouse(engo.AxisMouseHori))
 }
 
@@ -40,6 +92,16 @@ func (s *StartScene) Setup(u engo.Updater) {
 
 	common.SetBackground(color.RGBA{0x55, 0x55, 0x55, 0xFF})
 
+	// Tracks every entity, so the world can be emptied when it's replaced.
+	all := &entityList{}
+	var basicable *common.BasicFace
+	w.AddSystemInterface(all, basicable, nil)
+
+	// Gameplay systems run on TickSystem's fixed ticks; see tick.Add below.
+	tick := &systems.TickSystem{Speed: s.Speed}
+	var interpolatedable *systems.InterpolatedAble
+	w.AddSystemInterface(tick, interpolatedable, nil)
+
 	var renderable *common.Renderable
 	var notrenderable *common.NotRenderable
 	w.AddSystemInterface(&common.RenderSystem{}, renderable, notrenderable)
@@ -50,12 +112,14 @@ func (s *StartScene) Setup(u engo.Updater) {
 
 	var collisionable *common.Collisionable
 	var notcollisionable *common.NotCollisionable
-	w.AddSystemInterface(&common.CollisionSystem{Solids: systems.CollisionGroupPlaya | systems.CollisionGroupWall}, collisionable, notcollisionable)
+	collisionSystem := &systems.FixedCollisionSystem{CollisionSystem: &common.CollisionSystem{Solids: systems.CollisionGroupPlaya | systems.CollisionGroupWall}}
+	w.AddSystemInterface(collisionSystem, collisionable, notcollisionable)
 
 	var playermapable *systems.PlayerMapAble
 	var wallmapable *systems.WallMapAble
 	var notmapable *systems.NotMapAble
-	w.AddSystemInterface(&systems.MapSystem{}, []any{playermapable, wallmapable}, notmapable)
+	mapSystem := &systems.MapSystem{}
+	w.AddSystemInterface(mapSystem, []any{playermapable, wallmapable}, notmapable)
 
 	var playerviewable *systems.ViewPlayerAble
 	var wallviewable *systems.ViewWallAble
@@ -64,50 +128,385 @@ func (s *StartScene) Setup(u engo.Updater) {
 
 	var playeritemable *systems.ViewPlayerAble
 	var itemable *systems.ItemAble
-	w.AddSystemInterface(&systems.ItemSystem{}, []any{playeritemable, itemable}, nil)
+	itemSystem := &systems.ItemSystem{}
+	w.AddSystemInterface(itemSystem, []any{playeritemable, itemable}, nil)
+
+	var inputable *systems.InputAble
+	inputSystem := &systems.InputSystem{Controls: s.controls}
+	w.AddSystemInterface(inputSystem, inputable, nil)
+
+	var floorable *systems.FloorAble
+	var floorelevatorable *systems.ElevatorAble
+	floorSystem := &systems.FloorSystem{}
+	w.AddSystemInterface(floorSystem, []any{floorable, floorelevatorable}, nil)
 
 	var controlable *systems.ControlAble
-	w.AddSystemInterface(&systems.ControlSystem{}, controlable, nil)
+	controlSystem := &systems.ControlSystem{Floors: floorSystem}
+	w.AddSystemInterface(controlSystem, controlable, nil)
 
 	var lavaplayerable *systems.LavaPlayerAble
 	var lavazonable *systems.LavaZoneAble
-	w.AddSystemInterface(&systems.LavaSystem{}, []any{lavaplayerable, lavazonable}, nil)
+	lavaSystem := &systems.LavaSystem{}
+	w.AddSystemInterface(lavaSystem, []any{lavaplayerable, lavazonable}, nil)
 
 	var projectileplayerable *systems.ViewPlayerAble
 	var projectileable *systems.ProjectileAble
-	projectileSystem := &systems.ProjectileSystem{}
-	w.AddSystemInterface(projectileSystem, []any{projectileplayerable, projectileable}, nil)
+	var projectilewallable *systems.WallMapAble
+	var projectileenemyable *systems.EnemyAble
+	projectileSystem := &systems.ProjectileSystem{Floors: floorSystem}
+	w.AddSystemInterface(projectileSystem, []any{projectileplayerable, projectileable, projectilewallable, projectileenemyable}, nil)
 
 	var archeryable *systems.ArcheryAble
 	archerySystem := &systems.ArcherySystem{}
 	w.AddSystemInterface(archerySystem, archeryable, nil)
 
+	// Bombs, and the bomb counter on the HUD, use the same picture.
+	bombIcon, err := common.LoadedSprite("ui/bomb.png")
+	if err != nil {
+		println("Warning: failed to load bomb texture:", err.Error())
+	}
+	var bombTex *gl.Texture
+	if bombIcon != nil {
+		bombTex = bombIcon.Texture()
+	}
+	var bomberable *systems.BomberAble
+	var bombable *systems.BombAble
+	var bombplayerable *systems.ViewPlayerAble
+	var bombwallable *systems.WallMapAble
+	var bombenemyable *systems.EnemyAble
+	bombSystem := &systems.BombSystem{Floors: floorSystem, Tex: bombTex}
+	w.AddSystemInterface(bombSystem, []any{bomberable, bombable, bombplayerable, bombwallable, bombenemyable}, nil)
+
+	var meleeable *systems.MeleeAble
+	var meleeenemyable *systems.EnemyAble
+	meleeSystem := &systems.MeleeSystem{}
+	w.AddSystemInterface(meleeSystem, []any{meleeable, meleeenemyable}, nil)
+
+	var useplayerable *systems.ViewPlayerAble
+	var usewallable *systems.WallMapAble
+	useSystem := &systems.UseSystem{}
+	w.AddSystemInterface(useSystem, []any{useplayerable, usewallable}, nil)
+
+	var doorable *systems.DoorAble
+	doorSystem := &systems.DoorSystem{}
+	w.AddSystemInterface(doorSystem, doorable, nil)
+
+	var secretwallable *systems.SecretWallAble
+	var secretareaable *systems.SecretAreaAble
+	secretSystem := &systems.SecretSystem{}
+	w.AddSystemInterface(secretSystem, []any{secretwallable, secretareaable}, nil)
+
+	var switchable *systems.SwitchAble
+	w.AddSystemInterface(&systems.SwitchSystem{}, switchable, nil)
+
+	var elevatorplayerable *systems.ViewPlayerAble
+	var elevatorable *systems.ElevatorAble
+	elevatorSystem := &systems.ElevatorSystem{}
+	w.AddSystemInterface(elevatorSystem, []any{elevatorplayerable, elevatorable}, nil)
+
+	var teleportplayerable *systems.ViewPlayerAble
+	var markerable *systems.MarkerAble
+	var teleporterable *systems.TeleporterAble
+	var teleportprojectileable *systems.ProjectileAble
+	var teleportenemyable *systems.EnemyAble
+	teleportSystem := &systems.TeleportSystem{}
+	w.AddSystemInterface(teleportSystem, []any{teleportplayerable, markerable, teleporterable, teleportprojectileable, teleportenemyable}, nil)
+
+	var navwallable *systems.WallMapAble
+	navSystem := &systems.NavSystem{}
+	w.AddSystemInterface(navSystem, navwallable, nil)
+
+	var enemyplayerable *systems.ViewPlayerAble
+	var enemyable *systems.EnemyAble
+	var enemymarkerable *systems.MarkerAble
+	enemySystem := &systems.EnemySystem{Nav: navSystem, Projectiles: projectileSystem, Controls: s.controls}
+	w.AddSystemInterface(enemySystem, []any{enemyplayerable, enemyable, enemymarkerable}, nil)
+
+	var waveSystem *systems.WaveSystem
+	if s.Play != nil {
+		s.Survival = s.Play.Level == survivalLevel
+	}
+	if s.Survival {
+		def, err := systems.ParseWaves(startWaves)
+		if err != nil {
+			println("Warning: survival mode has no waves:", err.Error())
+		}
+		waveSystem = &systems.WaveSystem{Def: def, Enemies: enemySystem}
+		var waveplayerable *systems.ViewPlayerAble
+		var waveenemyable *systems.EnemyAble
+		var wavespawnerable *systems.SpawnerAble
+		w.AddSystemInterface(waveSystem, []any{waveplayerable, waveenemyable, wavespawnerable}, nil)
+	}
+
+	var inventoryable *systems.InventoryAble
+	w.AddSystemInterface(&systems.InventorySystem{BombIcon: bombIcon}, inventoryable, nil)
+
+	w.AddSystem(&systems.MessageSystem{})
+	w.AddSystem(&systems.BindingsMenuSystem{Controls: s.controls, Path: s.ControlsPath, Tick: tick})
+
+	// Demos: the same level, seed and commands reproduce a session.
+	var demoSystem *systems.DemoSystem
+	switch {
+	case s.Play != nil:
+		if s.Play.Level != s.level() {
+			println("Warning: demo was recorded on level", s.Play.Level, "not", s.level())
+		}
+		systems.SeedRand(s.Play.Seed)
+		demoSystem = &systems.DemoSystem{Demo: s.Play, Playback: true}
+		engo.Mailbox.Listen(systems.DemoEndMessageType, s.demoEnded)
+	case s.Record != "":
+		s.demo = &systems.Demo{Level: s.level(), Seed: time.Now().UnixNano()}
+		systems.SeedRand(s.demo.Seed)
+		demoSystem = &systems.DemoSystem{Demo: s.demo}
+	}
+	if demoSystem != nil {
+		var demoplayerable *systems.ViewPlayerAble
+		w.AddSystemInterface(demoSystem, demoplayerable, nil)
+	}
+
+	if s.SavePath == "" {
+		s.SavePath = "quicksave.sav"
+	}
+	saveSystem := &systems.SaveSystem{Path: s.SavePath, Level: s.level(), Controls: s.controls}
+	if demoSystem == nil {
+		// Loading sets the level up again from scratch, which a demo can't
+		// follow.
+		saveSystem.OnLoad = func(g *systems.SaveGame) {
+			s.load = g
+			// SetScene builds a new world and drops this one, so first
+			// take everything out of it, letting its systems let go.
+			all.clear(w)
+			engo.SetScene(s, true)
+		}
+	}
+	var saveplayerable *systems.ViewPlayerAble
+	var saveitemable *systems.ItemAble
+	var savedoorable *systems.DoorAble
+	var savedestructibleable *systems.DestructibleAble
+	var savesecretwallable *systems.SecretWallAble
+	var savesecretareaable *systems.SecretAreaAble
+	var saveelevatorable *systems.ElevatorAble
+	var saveprojectileable *systems.ProjectileAble
+	var savebombable *systems.BombAble
+	var saveenemyable *systems.EnemyAble
+	w.AddSystemInterface(saveSystem, []any{
+		saveplayerable, saveitemable, savedoorable, savedestructibleable,
+		savesecretwallable, savesecretareaable, saveelevatorable, saveprojectileable,
+		savebombable, saveenemyable,
+	}, nil)
+
+	// Each tick: fetch input commands and move, act on them, move everything
+	// else, then resolve collisions and what the player is standing in.
+	tick.Add(
+		inputSystem,
+		controlSystem,
+		meleeSystem,
+		useSystem,
+		doorSystem,
+		elevatorSystem,
+		navSystem,
+		enemySystem,
+		archerySystem,
+		projectileSystem,
+		bombSystem,
+		teleportSystem,
+		mapSystem,
+		collisionSystem,
+		lavaSystem,
+		secretSystem,
+		itemSystem,
+		saveSystem,
+	)
+	if waveSystem != nil {
+		tick.Add(waveSystem)
+	}
+	if demoSystem != nil {
+		tick.Add(demoSystem)
+	}
+
 	p := player{BasicEntity: ecs.NewBasic()}
+	p.Source = &systems.MergedSource{Sources: []systems.InputSource{
+		&systems.KeyboardMouseSource{Controls: s.controls},
+		&systems.GamepadSource{Controls: s.controls},
+	}}
 	p.Speed = 150
 	p.RotSpeed = 25
 	p.Height = 20
-	p.Ammo.Cap = 12
-	p.Ammo.Loaded = 8
-	p.Ammo.ReloadTime = 5.5
-	p.Ammo.TimeBtwnShots = 1
-	p.Ammo.ProjectileTex = shaders.CreateProjectileTexture(32)
+	p.Defense.IFrames = 0.5
+	p.Weapons = []systems.Ammunition{{
+		Name:          "Pistol",
+		Cap:           12,
+		Loaded:        8,
+		ReloadTime:    5.5,
+		TimeBtwnShots: 1,
+		ProjectileTex: shaders.CreateProjectileTexture(32),
+	}, {
+		// Hold fire to draw the bow; arrows drop and stick in walls.
+		Name:          "Bow",
+		Cap:           30,
+		Loaded:        30,
+		ReloadTime:    3,
+		TimeBtwnShots: 0.3,
+		ProjectileTex: shaders.CreateArrowTexture(32),
+		Speed:         500,
+		Damage:        50,
+		ChargeTime:    1,
+		MinCharge:     0.3,
+		Gravity:       250,
+		Stick:         5,
+	}, {
+		// Seeking orbs that bank off walls and burst on impact.
+		Name:          "Wand",
+		Cap:           6,
+		Loaded:        6,
+		ReloadTime:    4,
+		TimeBtwnShots: 0.8,
+		ProjectileTex: shaders.CreateProjectileTexture(32),
+		Speed:         250,
+		Damage:        20,
+		ProjectileBehaviour: systems.ProjectileBehaviour{
+			Bounces: 2,
+			Homing:  120,
+			Split:   3,
+		},
+	}, {
+		// Heavy bolts that go through two enemies into a third.
+		Name:          "Crossbow",
+		Cap:           1,
+		Loaded:        1,
+		ReloadTime:    1.5,
+		ProjectileTex: shaders.CreateArrowTexture(32),
+		Speed:         700,
+		Damage:        60,
+		Gravity:       60,
+		Stick:         5,
+		ProjectileBehaviour: systems.ProjectileBehaviour{
+			Pierce: 2,
+		},
+	}}
+	p.Ammo = p.Weapons[0]
+	p.AddBombs(3)
 	w.AddEntity(&p)
 
 	// Generate a single brick texture shared by all walls.
 	// CreateBrickTexture must be called after the GL context is ready (i.e. here in Setup).
 	brickTex := shaders.CreateBrickTexture(128, 128)
 
-	addWall := func(p1, p2 engo.Point, tex *gl.Texture) {
+	// addWall places a wall; tag may be empty, or name the wall so that
+	// switch actions can target it.
+	addWall := func(p1, p2 engo.Point, tex *gl.Texture, tag string) {
 		e := wall{BasicEntity: ecs.NewBasic()}
 		e.Wall = engo.Line{P1: p1, P2: p2}
 		e.Tex = tex
+		e.Tag = tag
 		w.AddEntity(&e)
 	}
 
-	addWall(engo.Point{X: -25, Y: 0}, engo.Point{X: 100, Y: 0}, brickTex)
-	addWall(engo.Point{X: 15, Y: 15}, engo.Point{X: 200, Y: 250}, brickTex)
-	addWall(engo.Point{X: 150, Y: 50}, engo.Point{X: 250, Y: -25}, brickTex)
-	addWall(engo.Point{X: 150, Y: 50}, engo.Point{X: 150, Y: -25}, brickTex)
+	addWall(engo.Point{X: -25, Y: 0}, engo.Point{X: 100, Y: 0}, brickTex, "hall")
+	addWall(engo.Point{X: 15, Y: 15}, engo.Point{X: 200, Y: 250}, brickTex, "hall")
+	addWall(engo.Point{X: 150, Y: 50}, engo.Point{X: 250, Y: -25}, brickTex, "")
+	addWall(engo.Point{X: 150, Y: 50}, engo.Point{X: 150, Y: -25}, brickTex, "")
+
+	// A cracked wall that gives way after a few shots.
+	cracked := breakableWall{BasicEntity: ecs.NewBasic()}
+	cracked.Wall = engo.Line{P1: engo.Point{X: 100, Y: 0}, P2: engo.Point{X: 150, Y: -25}}
+	cracked.Tex = brickTex
+	cracked.Health = 75
+	w.AddEntity(&cracked)
+
+	// A secret door sealing off the deep lava pit, and a hidden alcove.
+	hidden := secretDoor{BasicEntity: ecs.NewBasic()}
+	hidden.Wall = engo.Line{P1: engo.Point{X: 150, Y: -25}, P2: engo.Point{X: 250, Y: -25}}
+	hidden.Tex = brickTex
+	w.AddEntity(&hidden)
+
+	alcove := secretArea{BasicEntity: ecs.NewBasic()}
+	alcove.Position = engo.Point{X: -20, Y: 60}
+	alcove.Width, alcove.Height = 25, 25
+	w.AddEntity(&alcove)
+
+	// A red-locked door between the first two walls.
+	redDoor := door{BasicEntity: ecs.NewBasic()}
+	redDoor.Wall = engo.Line{P1: engo.Point{X: -25, Y: 0}, P2: engo.Point{X: 15, Y: 15}}
+	redDoor.Tex = brickTex
+	redDoor.Key = systems.KeyRed
+	w.AddEntity(&redDoor)
+
+	// A gate that only opens from the switch next to it. The switch also
+	// plunges the hall into darkness and calls in a skeleton ambush.
+	gate := door{BasicEntity: ecs.NewBasic()}
+	gate.Wall = engo.Line{P1: engo.Point{X: 200, Y: 250}, P2: engo.Point{X: 260, Y: 250}}
+	gate.Tex = brickTex
+	gate.Tag = "gate"
+	w.AddEntity(&gate)
+
+	lever := switchWall{BasicEntity: ecs.NewBasic()}
+	lever.Wall = engo.Line{P1: engo.Point{X: 260, Y: 250}, P2: engo.Point{X: 260, Y: 220}}
+	lever.Tex = brickTex
+	lever.Actions = []systems.Action{
+		{Kind: systems.ActionOpen, Target: "gate"},
+		{Kind: systems.ActionLight, Target: "hall"},
+		{Kind: systems.ActionSpawn, Target: "ambush"},
+	}
+	w.AddEntity(&lever)
+
+	// A one-shot switch in the alcove that teleports the player back out.
+	exit := marker{BasicEntity: ecs.NewBasic()}
+	exit.Position = engo.Point{X: 60, Y: 60}
+	exit.Name = "alcove-exit"
+	w.AddEntity(&exit)
+
+	warp := switchWall{BasicEntity: ecs.NewBasic()}
+	warp.Wall = engo.Line{P1: engo.Point{X: -25, Y: 90}, P2: engo.Point{X: 5, Y: 90}}
+	warp.Tex = brickTex
+	warp.Actions = []systems.Action{{Kind: systems.ActionTeleport, Target: "alcove-exit"}}
+	warp.Once = true
+	w.AddEntity(&warp)
+
+	// A teleporter pad behind the corner walls that sends the player, and
+	// any arrows shot onto it or enemies that follow, back out next to the
+	// start.
+	pad := teleporterPad{BasicEntity: ecs.NewBasic()}
+	pad.Position = engo.Point{X: 200, Y: 10}
+	pad.Width, pad.Height = 30, 30
+	pad.Destination = "pad-exit"
+	pad.Projectiles = true
+	pad.Enemies = true
+	w.AddEntity(&pad)
+
+	// A lift next to the start that rises and falls on its own, in a shaft
+	// tall enough to ride it above the walls.
+	lift := elevator{BasicEntity: ecs.NewBasic()}
+	lift.Position = engo.Point{X: 80, Y: 110}
+	lift.Width, lift.Height = 30, 30
+	lift.High = 30
+	lift.Wait = 3
+	lift.Ceiling = 120
+	lift.ElevatorComponent.Tex = brickTex
+	lift.Tag = "lift"
+	w.AddEntity(&lift)
+
+	// A flight of steps up to a ledge, and a block too high to step onto
+	// that has to be jumped onto.
+	addFloor := func(x, y, fw, fh, height float32) {
+		f := platform{BasicEntity: ecs.NewBasic()}
+		f.Position = engo.Point{X: x, Y: y}
+		f.Width, f.SpaceComponent.Height = fw, fh
+		f.FloorComponent.Height = height
+		f.Tex = brickTex
+		w.AddEntity(&f)
+	}
+	for step := float32(1); step <= 4; step++ {
+		addFloor(-20, 90+step*10, 30, 10, step*2.5)
+	}
+	addFloor(-20, 140, 30, 30, 10)
+	addFloor(100, 200, 30, 30, 6)
+
+	padExit := marker{BasicEntity: ecs.NewBasic()}
+	padExit.Position = engo.Point{X: 40, Y: 60}
+	padExit.Rotation = 90
+	padExit.Name = "pad-exit"
+	w.AddEntity(&padExit)
 
 	// addZone places a lava damage zone.  X/Y/W/H are in wall world-space
 	// (same coordinate system as wall endpoints above).
@@ -135,6 +534,48 @@ func (s *StartScene) Setup(u engo.Updater) {
 
 	// Link shooting system to projectile system
 	archerySystem.SetProjectileSystem(projectileSystem)
+	saveSystem.SetProjectileSystem(projectileSystem)
+	saveSystem.SetBombSystem(bombSystem)
+	saveSystem.SetEnemySystem(enemySystem)
+	if waveSystem != nil {
+		saveSystem.SetWaveSystem(waveSystem)
+	}
+
+	// Skeletons: one waiting behind the diagonal wall, and an ambush the
+	// gate's lever calls in behind the player.
+	// Arrows mostly pass between their ribs.
+	enemySystem.Spawn = systems.EnemyComponent{
+		Tex:     shaders.CreateSkeletonTexture(64),
+		Defense: systems.Defense{Resist: map[systems.DamageType]float32{systems.DamageProjectile: 0.3}},
+	}
+	if !s.Survival {
+		skeleton := enemy{BasicEntity: ecs.NewBasic()}
+		skeleton.Position = engo.Point{X: 180, Y: 120}
+		skeleton.EnemyComponent = enemySystem.Spawn
+		w.AddEntity(&skeleton)
+	}
+
+	for _, pos := range []engo.Point{{X: 220, Y: 200}, {X: 240, Y: 160}} {
+		ambush := marker{BasicEntity: ecs.NewBasic()}
+		ambush.Position = pos
+		ambush.Name = "ambush"
+		w.AddEntity(&ambush)
+	}
+
+	// Where survival waves come from.
+	for _, at := range []struct {
+		name string
+		pos  engo.Point
+	}{
+		{"north", engo.Point{X: 180, Y: 120}},
+		{"east", engo.Point{X: 240, Y: 160}},
+		{"south", engo.Point{X: 220, Y: 200}},
+	} {
+		sp := spawner{BasicEntity: ecs.NewBasic()}
+		sp.Position = at.pos
+		sp.Name = at.name
+		w.AddEntity(&sp)
+	}
 
 	// addItem places a pickupable potion at the given wall-space position.
 	addItem := func(pos engo.Point, effect systems.ItemEffect) {
@@ -155,4 +596,110 @@ func (s *StartScene) Setup(u engo.Updater) {
 	addItem(engo.Point{X: 120, Y: -10}, func() {
 		p.RotSpeed += 10 // turn-speed boost
 	})
+
+	// A shield that gives the player armor, up to 100.
+	armor := item{BasicEntity: ecs.NewBasic()}
+	armor.Position = engo.Point{X: 100, Y: 60}
+	armor.Tex = shaders.CreateArmorTexture(32)
+	armor.W = 16
+	armor.H = 16
+	armor.Radius = 15
+	armor.Effect = func() {
+		p.Defense.Armor += 50
+		if p.Defense.Armor > 100 {
+			p.Defense.Armor = 100
+		}
+	}
+	w.AddEntity(&armor)
+
+	// A bag of bombs.
+	bombs := item{BasicEntity: ecs.NewBasic()}
+	bombs.Position = engo.Point{X: 120, Y: 80}
+	bombs.Tex = bombTex
+	bombs.W = 12
+	bombs.H = 12
+	bombs.Radius = 15
+	bombs.Bombs = 3
+	w.AddEntity(&bombs)
+
+	// The red keycard that opens redDoor.
+	redKey := item{BasicEntity: ecs.NewBasic()}
+	redKey.Position = engo.Point{X: 60, Y: 100}
+	redKey.Tex = shaders.CreateKeycardTexture(32, systems.KeyColor(systems.KeyRed))
+	redKey.W = 12
+	redKey.H = 16
+	redKey.Radius = 15
+	redKey.Key = systems.KeyRed
+	w.AddEntity(&redKey)
+
+	// Quickloading sets the level up as new, then puts the save into it.
+	if s.load != nil {
+		if err := saveSystem.Restore(w, s.load); err != nil {
+			println("Warning: could not load the game:", err.Error())
+		} else {
+			engo.Mailbox.Dispatch(systems.HUDMessage{Text: "Game loaded"})
+		}
+		s.load = nil
+	}
+}
+
+// demoEnded reports how playback of s.Play went.
+func (s *StartScene) demoEnded(m engo.Message) {
+	msg, ok := m.(systems.DemoEndMessage)
+	if !ok {
+		return
+	}
+	if msg.Match {
+		fmt.Printf("Demo finished: %d ticks, checksum %08x matches\n", len(s.Play.Commands), msg.Checksum)
+	} else {
+		fmt.Printf("Demo finished: %d ticks, checksum %08x does not match recorded %08x\n",
+			len(s.Play.Commands), msg.Checksum, s.Play.Checksum)
+		s.DemoMismatch = true
+	}
+	if s.ExitAfterPlay {
+		engo.Exit()
+	}
+}
+
+// Exit saves the recording, if there is one.
+func (s *StartScene) Exit() {
+	if s.demo == nil {
+		return
+	}
+	if err := s.demo.Save(s.Record); err != nil {
+		println("Warning: could not save demo:", err.Error())
+	} else {
+		fmt.Printf("Demo saved to %s: %d ticks\n", s.Record, len(s.demo.Commands))
+	}
+	// engo may call Exit more than once while shutting down.
+	s.demo = nil
+}
+
+// entityList is a system that keeps a list of the entities in its world.
+type entityList struct {
+	entities []ecs.BasicEntity
+}
+
+func (l *entityList) AddByInterface(i ecs.Identifier) {
+	if o, ok := i.(common.BasicFace); ok {
+		l.entities = append(l.entities, *o.GetBasicEntity())
+	}
+}
+
+func (l *entityList) Remove(basic ecs.BasicEntity) {
+	for i, e := range l.entities {
+		if e.ID() == basic.ID() {
+			l.entities = append(l.entities[:i], l.entities[i+1:]...)
+			return
+		}
+	}
+}
+
+func (l *entityList) Update(dt float32) {}
+
+// clear removes every entity from w.
+func (l *entityList) clear(w *ecs.World) {
+	for _, e := range append([]ecs.BasicEntity(nil), l.entities...) {
+		w.RemoveEntity(e)
+	}
 }
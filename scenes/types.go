@@ -19,6 +19,107 @@ type wall struct {
 	common.SpaceComponent
 	systems.WallMapComponent
 	systems.ViewWallComponent
+	systems.TagComponent
+}
+
+// switchWall is a wall the player can use to run scripted actions.
+type switchWall struct {
+	ecs.BasicEntity
+
+	common.SpaceComponent
+	systems.WallMapComponent
+	systems.ViewWallComponent
+	systems.SwitchComponent
+}
+
+// marker is a named point in the level; Position is in wall world-space and
+// Rotation is the facing angle.
+type marker struct {
+	ecs.BasicEntity
+
+	common.SpaceComponent
+	systems.MarkerComponent
+}
+
+// teleporterPad is an area that sends the player (and, if Projectiles is
+// set, arrows) to the marker named by Destination.
+type teleporterPad struct {
+	ecs.BasicEntity
+
+	common.SpaceComponent
+	systems.TeleporterComponent
+}
+
+// elevator is a platform whose floor moves between two heights, carrying the
+// player with it. Its TagComponent lets switches raise and lower it.
+type elevator struct {
+	ecs.BasicEntity
+
+	common.SpaceComponent
+	systems.ElevatorComponent
+	systems.TagComponent
+}
+
+// enemy is a hostile that hunts the player down. EnemySystem draws it.
+type enemy struct {
+	ecs.BasicEntity
+
+	common.SpaceComponent
+	systems.EnemyComponent
+	systems.InterpolatedComponent
+}
+
+// platform is a raised area of floor, for steps and ledges.
+type platform struct {
+	ecs.BasicEntity
+
+	common.SpaceComponent
+	systems.FloorComponent
+}
+
+// breakableWall is a wall with hit-points; projectiles damage it and it is
+// removed from the world once destroyed.
+type breakableWall struct {
+	ecs.BasicEntity
+
+	common.SpaceComponent
+	systems.WallMapComponent
+	systems.ViewWallComponent
+	systems.DestructibleComponent
+}
+
+// door is a wall that sinks into the floor when the player uses it. Set Key
+// to lock it behind a keycard.
+type door struct {
+	ecs.BasicEntity
+
+	common.SpaceComponent
+	systems.WallMapComponent
+	systems.ViewWallComponent
+	systems.DoorComponent
+	systems.TagComponent
+}
+
+// secretDoor is a door that looks like any other wall and counts towards the
+// level's secrets once opened.
+type secretDoor struct {
+	ecs.BasicEntity
+
+	common.SpaceComponent
+	systems.WallMapComponent
+	systems.ViewWallComponent
+	systems.DoorComponent
+	systems.SecretComponent
+}
+
+// secretArea is a rectangular area, in wall world-space, that counts as a
+// found secret when the player first enters it.
+type secretArea struct {
+	ecs.BasicEntity
+
+	common.SpaceComponent
+	common.CollisionComponent
+	systems.SecretComponent
 }
 
 type player struct {
@@ -30,6 +131,10 @@ type player struct {
 	systems.PlayerMapComponent
 	systems.ControlComponent
 	systems.ViewPlayerComponent
+	systems.InventoryComponent
+	systems.InterpolatedComponent
+	systems.InputComponent
+	systems.MeleeComponent
 }
 
 type lavaZone struct {
@@ -70,6 +175,16 @@ type projectile struct {
 
 	common.SpaceComponent
 	systems.ProjectileComponent
+	systems.InterpolatedComponent
 	systems.NotMapComponent
 	systems.NotViewComponent
 }
+
+// spawner is a named place survival waves send enemies from, facing
+// Rotation.
+type spawner struct {
+	ecs.BasicEntity
+
+	common.SpaceComponent
+	systems.SpawnerComponent
+}
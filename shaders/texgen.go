@@ -85,7 +85,12 @@ func generateBrickImage(width, height int) *image.RGBA {
 }
 
 // uploadRGBATexture uploads an *image.RGBA to a new OpenGL texture object.
+// It returns nil when running headless, where there is no GL context; callers
+// fall back to untextured drawing.
 func uploadRGBATexture(img *image.RGBA) *gl.Texture {
+	if engo.Headless() {
+		return nil
+	}
 
 	tex := engo.Gl.CreateTexture()
 	engo.Gl.BindTexture(engo.Gl.TEXTURE_2D, tex)
@@ -337,3 +342,204 @@ func generateProjectileImage(size int) *image.RGBA {
 
 	return img
 }
+
+// CreateKeycardTexture generates a pixel-art keycard in the given colour and
+// uploads it to the GPU. size should be a power of two (e.g. 32, 64).
+// Must be called after the OpenGL context is initialised (i.e. from Setup).
+func CreateKeycardTexture(size int, c color.RGBA) *gl.Texture {
+	img := generateKeycardImage(size, c)
+	return uploadRGBATexture(img)
+}
+
+// generateKeycardImage produces an *image.RGBA with a coloured card, a dark
+// magnetic stripe and a light chip. Everything outside the card is transparent.
+func generateKeycardImage(size int, c color.RGBA) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, size, size))
+
+	// The card is a portrait rectangle standing on the bottom edge.
+	left, right := size/4, size-size/4
+	top := size / 6
+
+	stripe := color.RGBA{R: 30, G: 30, B: 30, A: 255}
+	chip := color.RGBA{R: 230, G: 220, B: 150, A: 255}
+	edge := color.RGBA{
+		R: uint8(float64(c.R) * 0.6),
+		G: uint8(float64(c.G) * 0.6),
+		B: uint8(float64(c.B) * 0.6),
+		A: 255,
+	}
+
+	for y := top; y < size; y++ {
+		for x := left; x < right; x++ {
+			px := c
+			switch {
+			case x == left || x == right-1 || y == top || y == size-1:
+				px = edge
+			case y >= top+size/10 && y < top+size/10+size/12:
+				px = stripe
+			case x >= left+size/10 && x < left+size/10+size/8 &&
+				y >= size/2 && y < size/2+size/10:
+				px = chip
+			}
+			img.SetRGBA(x, y, px)
+		}
+	}
+
+	return img
+}
+
+// CreateSkeletonTexture generates a pixel-art skeleton enemy and uploads it to
+// the GPU. size should be a power of two (e.g. 64).
+// Must be called after the OpenGL context is initialised (i.e. from Setup).
+func CreateSkeletonTexture(size int) *gl.Texture {
+	img := generateSkeletonImage(size)
+	return uploadRGBATexture(img)
+}
+
+// generateSkeletonImage produces an *image.RGBA with a standing skeleton: a
+// skull with dark eye sockets, a spine with ribs, arms, a pelvis and legs.
+// Everything else is transparent. The design scales with size.
+func generateSkeletonImage(size int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, size, size))
+
+	bone := color.RGBA{R: 230, G: 225, B: 205, A: 255}
+	shade := color.RGBA{R: 170, G: 165, B: 145, A: 255}
+	socket := color.RGBA{R: 20, G: 10, B: 10, A: 255}
+
+	s := float64(size)
+	fill := func(x0, y0, x1, y1 float64, c color.RGBA) {
+		for y := int(y0 * s); y < int(y1*s); y++ {
+			for x := int(x0 * s); x < int(x1*s); x++ {
+				img.SetRGBA(x, y, c)
+			}
+		}
+	}
+
+	// Skull: a disc over a narrower jaw.
+	cx, cy, rad := 0.5*s, 0.16*s, 0.12*s
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			dx, dy := float64(x)+0.5-cx, float64(y)+0.5-cy
+			if dx*dx+dy*dy <= rad*rad {
+				img.SetRGBA(x, y, bone)
+			}
+		}
+	}
+	fill(0.42, 0.24, 0.58, 0.31, shade)
+	fill(0.42, 0.13, 0.48, 0.19, socket)
+	fill(0.52, 0.13, 0.58, 0.19, socket)
+	fill(0.49, 0.2, 0.51, 0.23, socket)
+
+	// Spine and ribs.
+	fill(0.48, 0.31, 0.52, 0.6, bone)
+	for rib := 0; rib < 4; rib++ {
+		y := 0.35 + float64(rib)*0.055
+		fill(0.36, y, 0.64, y+0.025, shade)
+	}
+
+	// Arms hanging from the shoulders.
+	fill(0.3, 0.32, 0.7, 0.35, bone)
+	fill(0.28, 0.32, 0.31, 0.6, bone)
+	fill(0.69, 0.32, 0.72, 0.6, bone)
+
+	// Pelvis and legs.
+	fill(0.4, 0.6, 0.6, 0.65, shade)
+	fill(0.41, 0.65, 0.44, 0.97, bone)
+	fill(0.56, 0.65, 0.59, 0.97, bone)
+	fill(0.37, 0.95, 0.45, 1, bone)
+	fill(0.55, 0.95, 0.63, 1, bone)
+
+	return img
+}
+
+// CreateArmorTexture generates a pixel-art shield for armor pickups and
+// uploads it to the GPU. size should be a power of two (e.g. 32, 64).
+// Must be called after the OpenGL context is initialised (i.e. from Setup).
+func CreateArmorTexture(size int) *gl.Texture {
+	img := generateArmorImage(size)
+	return uploadRGBATexture(img)
+}
+
+// generateArmorImage produces an *image.RGBA with a blue heater shield: square
+// at the top, tapering to a point at the bottom, with a darker rim and a light
+// boss in the middle. Everything outside the shield is transparent.
+func generateArmorImage(size int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, size, size))
+
+	face := color.RGBA{R: 0x33, G: 0x88, B: 0xff, A: 255}
+	rim := color.RGBA{R: 0x1a, G: 0x3d, B: 0x80, A: 255}
+	boss := color.RGBA{R: 0xcc, G: 0xe0, B: 0xff, A: 255}
+
+	mid := float64(size) / 2
+	half := float64(size) * 0.4 // half the width at the top
+	top, waist := float64(size)/8, float64(size)/2
+	rimW := float64(size) / 16
+
+	for y := 0; y < size; y++ {
+		py := float64(y) + 0.5
+		if py < top {
+			continue
+		}
+		// Full width down to the waist, then narrowing to the point.
+		w := half
+		if py > waist {
+			w = half * (1 - (py-waist)/(float64(size)-waist))
+		}
+		for x := 0; x < size; x++ {
+			dx := math.Abs(float64(x) + 0.5 - mid)
+			if dx > w {
+				continue
+			}
+			px := face
+			switch {
+			case dx > w-rimW || py < top+rimW:
+				px = rim
+			case dx*dx+(py-waist)*(py-waist) < float64(size*size)/100:
+				px = boss
+			}
+			img.SetRGBA(x, y, px)
+		}
+	}
+
+	return img
+}
+
+// CreateArrowTexture generates a pixel-art arrow for bow shots and uploads it
+// to the GPU. size should be a power of two (e.g. 32, 64).
+// Must be called after the OpenGL context is initialised (i.e. from Setup).
+func CreateArrowTexture(size int) *gl.Texture {
+	img := generateArrowImage(size)
+	return uploadRGBATexture(img)
+}
+
+// generateArrowImage produces an *image.RGBA with an arrow lying diagonally,
+// its iron head at the top right and its fletching at the bottom left.
+// Everything else is transparent.
+func generateArrowImage(size int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, size, size))
+
+	shaft := color.RGBA{R: 0x8b, G: 0x5a, B: 0x2b, A: 255}
+	head := color.RGBA{R: 0xcc, G: 0xcc, B: 0xd0, A: 255}
+	feather := color.RGBA{R: 0xee, G: 0x33, B: 0x33, A: 255}
+
+	s := float64(size)
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			// u runs along the arrow from its tail (0) to its tip (1), v
+			// across it.
+			px, py := float64(x)+0.5, s-(float64(y)+0.5)
+			u := (px + py) / (2 * s)
+			v := math.Abs(px-py) / s
+			switch {
+			case u > 0.8 && u < 0.95 && v < (0.95-u)*0.8:
+				img.SetRGBA(x, y, head)
+			case u > 0.05 && u < 0.25 && v < 0.08:
+				img.SetRGBA(x, y, feather)
+			case u > 0.05 && u <= 0.8 && v < 0.03:
+				img.SetRGBA(x, y, shaft)
+			}
+		}
+	}
+
+	return img
+}
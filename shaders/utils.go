@@ -23,6 +23,10 @@ type Wall struct {
 	Line engo.Line
 	Tex  *gl.Texture
 	H    float32
+	// Z shifts the quad by that many world units away from the top of a
+	// standard wall, so that e.g. the edge of a raised floor can stand on
+	// the floor rather than hang from the top.
+	Z float32
 }
 
 func (w Wall) Texture() *gl.Texture { return w.Tex }
@@ -40,6 +44,9 @@ type Billboard struct {
 	Pos  engo.Point
 	W, H float32
 	Tex  *gl.Texture
+	// Z shifts the sprite by that many world units, the same way as Wall's
+	// Z, e.g. to show something flying through the air.
+	Z float32
 }
 
 func (b Billboard) Texture() *gl.Texture                     { return b.Tex }
@@ -34,6 +34,7 @@ type viewShader struct {
 	playerOffset engo.Point
 	fovAngleDeg  float32
 	tanHalfFov   float32
+	shake        engo.Point
 }
 
 func (s *viewShader) Setup(w *ecs.World) error {
@@ -141,8 +142,8 @@ func (s *viewShader) Pre() {
 		s.projectionMatrix[4] = 1 / (-engo.CanvasHeight() / (2 * engo.CanvasScale()))
 	}
 
-	s.viewMatrix[6] = -1 / s.projectionMatrix[0]
-	s.viewMatrix[7] = 1 / s.projectionMatrix[4]
+	s.viewMatrix[6] = -1/s.projectionMatrix[0] + s.shake.X
+	s.viewMatrix[7] = 1/s.projectionMatrix[4] + s.shake.Y
 
 	engo.Gl.UniformMatrix3fv(s.matrixProjection, false, s.projectionMatrix)
 	engo.Gl.UniformMatrix3fv(s.matrixView, false, s.viewMatrix)
@@ -212,16 +213,16 @@ func (s *viewShader) generateBufferContent(ren *common.RenderComponent, space *c
 		p2Y := (-p2.Y + (s.player.Position.Y - s.playerOffset.Y))
 		x0 := (p1X*cos - p1Y*sin)
 		y0 := (p1Y*cos + p1X*sin)
-		z0 := -1 * s.player.Height
+		z0 := -1*s.player.Height + d.Z
 		x1 := (p2X*cos - p2Y*sin)
 		y1 := (p2Y*cos + p2X*sin)
-		z1 := -1 * s.player.Height
+		z1 := -1*s.player.Height + d.Z
 		x2 := x0
 		y2 := y0
-		z2 := -1*s.player.Height + d.H
+		z2 := -1*s.player.Height + d.Z + d.H
 		x3 := x1
 		y3 := y1
-		z3 := -1*s.player.Height + d.H
+		z3 := -1*s.player.Height + d.Z + d.H
 
 		const near float32 = 1.0
 
@@ -383,10 +384,10 @@ func (s *viewShader) generateBufferContent(ren *common.RenderComponent, space *c
 		}
 
 		// Billboard extends W/2 to each side at uniform depth.
-		x0 := camX - d.W/2             // left edge in camera space
-		x1 := camX + d.W/2             // right edge in camera space
-		zBot := -s.player.Height       // floor level (same convention as Wall z0)
-		zTop := -s.player.Height + d.H // top of billboard (same as Wall z2)
+		x0 := camX - d.W/2                   // left edge in camera space
+		x1 := camX + d.W/2                   // right edge in camera space
+		zBot := -s.player.Height + d.Z       // floor level (same convention as Wall z0)
+		zTop := -s.player.Height + d.Z + d.H // top of billboard (same as Wall z2)
 
 		u0, u1 := float32(0), float32(1)
 
@@ -571,3 +572,15 @@ func (s *viewShader) SetCamera(*common.CameraSystem) {}
 func (s *viewShader) AddPlayer(space *common.SpaceComponent) {
 	s.player = space
 }
+
+// RemovePlayer detaches the camera from the player. Nothing is drawn by the
+// shader until AddPlayer is called again.
+func (s *viewShader) RemovePlayer() {
+	s.player = nil
+}
+
+// Shake moves the whole 3D view by off, in screen units, e.g. to shake it
+// with an explosion. It stays moved until Shake is called again.
+func (s *viewShader) Shake(off engo.Point) {
+	s.shake = off
+}
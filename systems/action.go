@@ -0,0 +1,149 @@
+package systems
+
+import (
+	"github.com/EngoEngine/ecs"
+	"github.com/EngoEngine/engo"
+	"github.com/EngoEngine/engo/common"
+)
+
+// ActionKind names something a switch (or other trigger) can make happen to
+// the entities carrying its target tag.
+type ActionKind string
+
+const (
+	// ActionOpen, ActionClose and ActionToggle operate tagged doors.
+	ActionOpen   ActionKind = "open"
+	ActionClose  ActionKind = "close"
+	ActionToggle ActionKind = "toggle"
+	// ActionRaise and ActionLower move tagged floors.
+	ActionRaise ActionKind = "raise"
+	ActionLower ActionKind = "lower"
+	// ActionLight switches the lights on tagged walls off or back on.
+	ActionLight ActionKind = "light"
+	// ActionSpawn spawns enemies at the markers named Target.
+	ActionSpawn ActionKind = "spawn"
+	// ActionTeleport moves the player to the marker named Target.
+	ActionTeleport ActionKind = "teleport"
+)
+
+// Action is one scripted level action: do Kind to everything tagged Target.
+// Actions are plain data so that levels can describe them without Go code.
+type Action struct {
+	Kind ActionKind
+	// Target is the tag of the entities to act on, or for ActionSpawn and
+	// ActionTeleport the Name of the marker(s) to use.
+	Target string
+}
+
+// ActionMessageType is the engo.Mailbox type of ActionMessage.
+const ActionMessageType = "ActionMessage"
+
+// ActionMessage carries an Action to every system that owns taggable
+// entities. Each system handles the kinds that apply to it and ignores the
+// rest.
+type ActionMessage struct {
+	Action
+}
+
+func (ActionMessage) Type() string { return ActionMessageType }
+
+// TagComponent names an entity so that Actions can target it. Several
+// entities may share a tag, and are then operated together.
+type TagComponent struct {
+	Tag string
+}
+
+func (c *TagComponent) GetTagComponent() *TagComponent { return c }
+
+// TagFace is satisfied by anything that embeds *TagComponent.
+type TagFace interface {
+	GetTagComponent() *TagComponent
+}
+
+// tagOf returns i's tag, or "" when it has none.
+func tagOf(i ecs.Identifier) string {
+	if o, ok := i.(TagFace); ok {
+		return o.GetTagComponent().Tag
+	}
+	return ""
+}
+
+// SwitchComponent turns a wall into a switch that runs Actions when the
+// player faces it and presses use.
+type SwitchComponent struct {
+	Actions []Action
+	// Key, when set, makes the switch only work for a player carrying that
+	// keycard.
+	Key Key
+	// Once disables the switch after its first use.
+	Once bool
+
+	used bool
+}
+
+func (c *SwitchComponent) GetSwitchComponent() *SwitchComponent { return c }
+
+// SwitchFace is satisfied by anything that embeds *SwitchComponent.
+type SwitchFace interface {
+	GetSwitchComponent() *SwitchComponent
+}
+
+// SwitchAble is the interface AddByInterface uses to detect switch walls.
+type SwitchAble interface {
+	common.BasicFace
+	SwitchFace
+	WallMapFace
+}
+
+type switchEntity struct {
+	*ecs.BasicEntity
+	*SwitchComponent
+}
+
+// SwitchSystem runs a switch's Actions, as ActionMessages, when UseSystem
+// reports that the player used it.
+type SwitchSystem struct {
+	switches []switchEntity
+}
+
+func (s *SwitchSystem) New(w *ecs.World) {
+	engo.Mailbox.Listen(UseMessageType, func(msg engo.Message) {
+		m, ok := msg.(UseMessage)
+		if !ok {
+			return
+		}
+		for _, sw := range s.switches {
+			if sw.ID() != m.Target.ID() {
+				continue
+			}
+			if sw.Once && sw.used {
+				return
+			}
+			if !unlocks(m.Inventory, sw.Key) {
+				return
+			}
+			sw.used = true
+			for _, a := range sw.Actions {
+				engo.Mailbox.Dispatch(ActionMessage{a})
+			}
+			return
+		}
+	})
+}
+
+func (s *SwitchSystem) AddByInterface(i ecs.Identifier) {
+	if o, ok := i.(SwitchAble); ok {
+		s.switches = append(s.switches, switchEntity{o.GetBasicEntity(), o.GetSwitchComponent()})
+	}
+}
+
+func (s *SwitchSystem) Remove(basic ecs.BasicEntity) {
+	for i, sw := range s.switches {
+		if sw.ID() == basic.ID() {
+			s.switches = append(s.switches[:i], s.switches[i+1:]...)
+			return
+		}
+	}
+}
+
+func (s *SwitchSystem) Update(dt float32) {}
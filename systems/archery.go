@@ -1,16 +1,48 @@
 package systems
 
 import (
+	"image/color"
+
 	"github.com/EngoEngine/ecs"
+	"github.com/EngoEngine/engo"
 	"github.com/EngoEngine/engo/common"
 	"github.com/EngoEngine/gl"
 )
 
+const (
+	// The charge bar, centred below the middle of the screen.
+	chargeBarX float32 = 290
+	chargeBarY float32 = 200
+	chargeBarW float32 = 60
+	chargeBarH float32 = 4
+)
+
+// Ammunition is a weapon: what it fires and how.
 type Ammunition struct {
+	// Name is shown when the weapon is picked.
+	Name          string
 	ProjectileTex *gl.Texture
 	ReloadTime    float32
 	TimeBtwnShots float32
 	Loaded, Cap   int
+	// Speed and Damage are those of a fully charged shot. Zero means
+	// projectileSpeed and projectileDamage.
+	Speed, Damage float32
+	// ChargeTime is how many seconds fire has to be held for a fully
+	// charged shot, which is fired when it is let go. Zero fires as soon
+	// as fire is pressed, at full strength.
+	ChargeTime float32
+	// MinCharge is the strength, from 0 to 1, of a shot let go of straight
+	// away; it grows to 1 over ChargeTime.
+	MinCharge float32
+	// Gravity pulls shots down, in world units per second². Zero flies
+	// flat.
+	Gravity float32
+	// Stick is how many seconds shots stay stuck in the walls and floors
+	// they hit. Zero makes them vanish.
+	Stick float32
+	// ProjectileBehaviour is how shots bounce, pierce, home and split.
+	ProjectileBehaviour
 }
 
 // ArcheryComponent is the ability to fire projectiles.
@@ -18,13 +50,56 @@ type ArcheryComponent struct {
 	IsShooting   bool
 	IsReloading  bool
 	ShotCooldown float32
-	Ammo         Ammunition
+	// Ammo is the weapon in hand.
+	Ammo Ammunition
+	// Weapons are the weapons that can be picked with the weapon slots, in
+	// order. Ammo is kept here while another is in hand.
+	Weapons []Ammunition
+
+	weapon   int     // index in Weapons of the weapon in hand
+	charge   float32 // seconds fire has been held for
+	charging bool
 }
 
 func (c *ArcheryComponent) GetArcheryComponent() *ArcheryComponent {
 	return c
 }
 
+// Weapon returns the index in Weapons of the weapon in hand.
+func (c *ArcheryComponent) Weapon() int { return c.weapon }
+
+// SelectWeapon takes the weapon in slot, counting from 1, in hand, if there
+// is one and it isn't already.
+func (c *ArcheryComponent) SelectWeapon(slot int) {
+	if slot < 1 || slot > len(c.Weapons) || slot-1 == c.weapon {
+		return
+	}
+	c.arm(slot - 1)
+	if c.Ammo.Name != "" {
+		engo.Mailbox.Dispatch(HUDMessage{Text: c.Ammo.Name})
+	}
+}
+
+// arm puts Ammo away in Weapons and takes Weapons[i] in hand, dropping any
+// charge.
+func (c *ArcheryComponent) arm(i int) {
+	if c.weapon < len(c.Weapons) {
+		c.Weapons[c.weapon] = c.Ammo
+	}
+	c.weapon = i
+	c.Ammo = c.Weapons[i]
+	c.charge, c.charging = 0, false
+}
+
+// Charge returns how far the shot being charged is, from 0 to 1, and
+// whether one is being charged at all.
+func (c *ArcheryComponent) Charge() (float32, bool) {
+	if !c.charging || c.Ammo.ChargeTime <= 0 {
+		return 0, false
+	}
+	return c.charge / c.Ammo.ChargeTime, true
+}
+
 type ArcheryFace interface {
 	GetArcheryComponent() *ArcheryComponent
 }
@@ -44,10 +119,45 @@ type archeryEntity struct {
 // ArcherySystem is the system that handles the firing of projectiles. Any
 // entity with an ArcheryComponent can fire a projectile. This system
 // handles the spawning of the projectile and associated animations.
-// Projectiles are then handled by the Projectile system.
+// Projectiles are then handled by the Projectile system. Weapons with a
+// ChargeTime charge while fire is held, shown by a bar on the HUD, and fire
+// when it is let go.
 type ArcherySystem struct {
 	entities         []archeryEntity
 	projectileSystem *ProjectileSystem
+
+	chargeBarBg, chargeBarFg hudBar
+}
+
+func (s *ArcherySystem) New(w *ecs.World) {
+	s.chargeBarBg = hudBar{BasicEntity: ecs.NewBasic()}
+	s.chargeBarBg.SpaceComponent = common.SpaceComponent{
+		Position: engo.Point{X: chargeBarX, Y: chargeBarY},
+		Width:    chargeBarW,
+		Height:   chargeBarH,
+	}
+	s.chargeBarBg.RenderComponent = common.RenderComponent{
+		Drawable:    common.Rectangle{},
+		Color:       color.RGBA{0x30, 0x30, 0x30, 0xCC},
+		StartZIndex: 10,
+		Hidden:      true,
+	}
+	s.chargeBarBg.SetShader(common.LegacyHUDShader)
+	w.AddEntity(&s.chargeBarBg)
+
+	s.chargeBarFg = hudBar{BasicEntity: ecs.NewBasic()}
+	s.chargeBarFg.SpaceComponent = common.SpaceComponent{
+		Position: engo.Point{X: chargeBarX, Y: chargeBarY},
+		Height:   chargeBarH,
+	}
+	s.chargeBarFg.RenderComponent = common.RenderComponent{
+		Drawable:    common.Rectangle{},
+		Color:       color.RGBA{0xFF, 0xCC, 0x33, 0xFF},
+		StartZIndex: 11,
+		Hidden:      true,
+	}
+	s.chargeBarFg.SetShader(common.LegacyHUDShader)
+	w.AddEntity(&s.chargeBarFg)
 }
 
 // SetProjectileSystem links this system to the ProjectileSystem so it can
@@ -78,19 +188,44 @@ func (s *ArcherySystem) Remove(basic ecs.BasicEntity) {
 	}
 }
 
+// Update shows the charge bar while a shot is being charged; shots and
+// reloads are timed in Tick.
 func (s *ArcherySystem) Update(dt float32) {
+	var charge float32
+	charging := false
+	for _, entity := range s.entities {
+		if c, ok := entity.Charge(); ok {
+			charge, charging = c, true
+		}
+	}
+	s.chargeBarBg.Hidden = !charging
+	s.chargeBarFg.Hidden = !charging
+	s.chargeBarFg.Width = chargeBarW * charge
+}
+
+func (s *ArcherySystem) Tick(dt float32) {
 	for _, entity := range s.entities {
 		entity.ShotCooldown -= dt
 		if entity.ShotCooldown <= 0 {
-			if entity.IsShooting && entity.Ammo.Loaded > 0 {
-				s.projectileSystem.SpawnProjectile(entity.Ammo.ProjectileTex)
-				entity.Ammo.Loaded -= 1
-				entity.SelectAnimationByName("shoot")
-				entity.ShotCooldown = entity.Ammo.TimeBtwnShots
+			ammo := &entity.Ammo
+			switch {
+			case ammo.ChargeTime <= 0:
+				if entity.IsShooting && ammo.Loaded > 0 {
+					s.fire(entity, 1)
+				}
+			case entity.IsShooting && ammo.Loaded > 0 && !entity.IsReloading:
+				entity.charging = true
+				entity.charge += dt
+				if entity.charge > ammo.ChargeTime {
+					entity.charge = ammo.ChargeTime
+				}
+			case entity.charging:
+				s.fire(entity, ammo.MinCharge+(1-ammo.MinCharge)*entity.charge/ammo.ChargeTime)
 			}
 		}
 		if entity.ShotCooldown <= 0 {
 			if entity.IsReloading {
+				entity.charge, entity.charging = 0, false
 				entity.Ammo.Loaded = entity.Ammo.Cap
 				entity.SelectAnimationByName("reload")
 				entity.ShotCooldown = entity.Ammo.ReloadTime
@@ -101,3 +236,13 @@ func (s *ArcherySystem) Update(dt float32) {
 		}
 	}
 }
+
+// fire shoots one of entity's Ammo at strength, from 0 to 1 of a fully
+// charged shot.
+func (s *ArcherySystem) fire(entity archeryEntity, strength float32) {
+	s.projectileSystem.SpawnProjectile(&entity.Ammo, strength)
+	entity.Ammo.Loaded -= 1
+	entity.SelectAnimationByName("shoot")
+	entity.ShotCooldown = entity.Ammo.TimeBtwnShots
+	entity.charge, entity.charging = 0, false
+}
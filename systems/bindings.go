@@ -0,0 +1,374 @@
+package systems
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/EngoEngine/engo"
+)
+
+// controlsVersion is the version of the controls config file. Files from
+// other versions are ignored in favour of the defaults.
+const controlsVersion = 1
+
+// InputControl describes one rebindable control: the engo.Input button it is
+// registered as and how it is shown on the bindings menu.
+type InputControl struct {
+	Name  string
+	Label string
+}
+
+// InputControls lists every rebindable control, in menu order.
+var InputControls = []InputControl{
+	{"up", "Move forward"},
+	{"down", "Move back"},
+	{"left", "Strafe left"},
+	{"right", "Strafe right"},
+	{"fire", "Fire"},
+	{"reload", "Reload"},
+	{"use", "Use"},
+	{"jump", "Jump"},
+	{"sprint", "Sprint"},
+	{"crouch", "Crouch"},
+	{"dash", "Dash"},
+	{"melee", "Melee"},
+	{"throw", "Throw bomb"},
+	{"weapon1", "Weapon 1"},
+	{"weapon2", "Weapon 2"},
+	{"weapon3", "Weapon 3"},
+	{"weapon4", "Weapon 4"},
+	{"quicksave", "Quicksave"},
+	{"quickload", "Quickload"},
+	{"bindings", "Controls menu"},
+	{"paths", "Show enemy paths"},
+}
+
+// WeaponSlots is the number of "weaponN" controls.
+const WeaponSlots = 4
+
+// Binding is one key or mouse button that can trigger a control. In the
+// config file it is written by name, e.g. "W", "ArrowUp" or "MouseLeft".
+type Binding struct {
+	Key    engo.Key
+	Mouse  bool // Button is used rather than Key
+	Button engo.MouseButton
+}
+
+// KeyBinding binds a key.
+func KeyBinding(k engo.Key) Binding { return Binding{Key: k} }
+
+// MouseBinding binds a mouse button.
+func MouseBinding(b engo.MouseButton) Binding { return Binding{Mouse: true, Button: b} }
+
+// keyNames names the bindable keys, in the order the bindings menu scans
+// them.
+var keyNames = []struct {
+	name string
+	key  engo.Key
+}{
+	{"A", engo.KeyA}, {"B", engo.KeyB}, {"C", engo.KeyC}, {"D", engo.KeyD},
+	{"E", engo.KeyE}, {"F", engo.KeyF}, {"G", engo.KeyG}, {"H", engo.KeyH},
+	{"I", engo.KeyI}, {"J", engo.KeyJ}, {"K", engo.KeyK}, {"L", engo.KeyL},
+	{"M", engo.KeyM}, {"N", engo.KeyN}, {"O", engo.KeyO}, {"P", engo.KeyP},
+	{"Q", engo.KeyQ}, {"R", engo.KeyR}, {"S", engo.KeyS}, {"T", engo.KeyT},
+	{"U", engo.KeyU}, {"V", engo.KeyV}, {"W", engo.KeyW}, {"X", engo.KeyX},
+	{"Y", engo.KeyY}, {"Z", engo.KeyZ},
+	{"0", engo.KeyZero}, {"1", engo.KeyOne}, {"2", engo.KeyTwo},
+	{"3", engo.KeyThree}, {"4", engo.KeyFour}, {"5", engo.KeyFive},
+	{"6", engo.KeySix}, {"7", engo.KeySeven}, {"8", engo.KeyEight},
+	{"9", engo.KeyNine},
+	{"F1", engo.KeyF1}, {"F2", engo.KeyF2}, {"F3", engo.KeyF3},
+	{"F4", engo.KeyF4}, {"F5", engo.KeyF5}, {"F6", engo.KeyF6},
+	{"F7", engo.KeyF7}, {"F8", engo.KeyF8}, {"F9", engo.KeyF9},
+	{"F10", engo.KeyF10}, {"F11", engo.KeyF11}, {"F12", engo.KeyF12},
+	{"ArrowUp", engo.KeyArrowUp}, {"ArrowDown", engo.KeyArrowDown},
+	{"ArrowLeft", engo.KeyArrowLeft}, {"ArrowRight", engo.KeyArrowRight},
+	{"Space", engo.KeySpace}, {"Enter", engo.KeyEnter}, {"Tab", engo.KeyTab},
+	{"Backspace", engo.KeyBackspace}, {"CapsLock", engo.KeyCapsLock},
+	{"LeftShift", engo.KeyLeftShift}, {"RightShift", engo.KeyRightShift},
+	{"LeftControl", engo.KeyLeftControl}, {"RightControl", engo.KeyRightControl},
+	{"LeftAlt", engo.KeyLeftAlt}, {"RightAlt", engo.KeyRightAlt},
+	{"Insert", engo.KeyInsert}, {"Delete", engo.KeyDelete},
+	{"Home", engo.KeyHome}, {"End", engo.KeyEnd},
+	{"PageUp", engo.KeyPageUp}, {"PageDown", engo.KeyPageDown},
+	{"Grave", engo.KeyGrave}, {"Dash", engo.KeyDash}, {"Equals", engo.KeyEquals},
+	{"LeftBracket", engo.KeyLeftBracket}, {"RightBracket", engo.KeyRightBracket},
+	{"Semicolon", engo.KeySemicolon}, {"Apostrophe", engo.KeyApostrophe},
+	{"Comma", engo.KeyComma}, {"Period", engo.KeyPeriod},
+	{"Slash", engo.KeySlash}, {"Backslash", engo.KeyBackslash},
+	{"Num0", engo.KeyNumZero}, {"Num1", engo.KeyNumOne}, {"Num2", engo.KeyNumTwo},
+	{"Num3", engo.KeyNumThree}, {"Num4", engo.KeyNumFour}, {"Num5", engo.KeyNumFive},
+	{"Num6", engo.KeyNumSix}, {"Num7", engo.KeyNumSeven}, {"Num8", engo.KeyNumEight},
+	{"Num9", engo.KeyNumNine}, {"NumEnter", engo.KeyNumEnter},
+	{"NumAdd", engo.KeyNumAdd}, {"NumSubtract", engo.KeyNumSubtract},
+	{"NumMultiply", engo.KeyNumMultiply}, {"NumDivide", engo.KeyNumDivide},
+	{"NumDecimal", engo.KeyNumDecimal},
+}
+
+// mouseNames names the bindable mouse buttons.
+var mouseNames = []struct {
+	name   string
+	button engo.MouseButton
+}{
+	{"MouseLeft", engo.MouseButtonLeft},
+	{"MouseRight", engo.MouseButtonRight},
+	{"MouseMiddle", engo.MouseButtonMiddle},
+	{"Mouse4", engo.MouseButton4},
+	{"Mouse5", engo.MouseButton5},
+}
+
+func (b Binding) String() string {
+	if b.Mouse {
+		for _, m := range mouseNames {
+			if m.button == b.Button {
+				return m.name
+			}
+		}
+		return fmt.Sprintf("Mouse%d", int(b.Button)+1)
+	}
+	for _, k := range keyNames {
+		if k.key == b.Key {
+			return k.name
+		}
+	}
+	return fmt.Sprintf("Key%d", int(b.Key))
+}
+
+func (b Binding) MarshalText() ([]byte, error) { return []byte(b.String()), nil }
+
+func (b *Binding) UnmarshalText(text []byte) error {
+	name := string(text)
+	for _, m := range mouseNames {
+		if strings.EqualFold(m.name, name) {
+			*b = MouseBinding(m.button)
+			return nil
+		}
+	}
+	for _, k := range keyNames {
+		if strings.EqualFold(k.name, name) {
+			*b = KeyBinding(k.key)
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown key or mouse button %q", name)
+}
+
+// ControlsConfig is the player's input configuration: what each control is
+// bound to, and how the mouse and gamepad turn the view.
+type ControlsConfig struct {
+	Version  int
+	Bindings map[string][]Binding
+	// MouseSensitivity scales mouse turning; 1 is the default speed.
+	MouseSensitivity float32
+	// InvertMouse turns the view the opposite way to the mouse.
+	InvertMouse bool
+	// Gamepad holds the gamepad's bindings and stick settings.
+	Gamepad GamepadConfig
+
+	// suspended is set while the bindings menu is open, so that keys
+	// pressed on it don't also reach the game.
+	suspended bool
+}
+
+// DefaultControls returns the standard bindings.
+func DefaultControls() *ControlsConfig {
+	k := KeyBinding
+	return &ControlsConfig{
+		Version: controlsVersion,
+		Bindings: map[string][]Binding{
+			"up":        {k(engo.KeyW), k(engo.KeyArrowUp)},
+			"down":      {k(engo.KeyS), k(engo.KeyArrowDown)},
+			"left":      {k(engo.KeyA), k(engo.KeyArrowLeft)},
+			"right":     {k(engo.KeyD), k(engo.KeyArrowRight)},
+			"fire":      {MouseBinding(engo.MouseButtonLeft)},
+			"reload":    {k(engo.KeyR)},
+			"use":       {k(engo.KeyE)},
+			"jump":      {k(engo.KeySpace)},
+			"sprint":    {k(engo.KeyLeftShift), k(engo.KeyRightShift)},
+			"crouch":    {k(engo.KeyLeftControl), k(engo.KeyRightControl)},
+			"dash":      {k(engo.KeyLeftAlt), k(engo.KeyQ)},
+			"melee":     {k(engo.KeyV), MouseBinding(engo.MouseButtonRight)},
+			"throw":     {k(engo.KeyG)},
+			"weapon1":   {k(engo.KeyOne)},
+			"weapon2":   {k(engo.KeyTwo)},
+			"weapon3":   {k(engo.KeyThree)},
+			"weapon4":   {k(engo.KeyFour)},
+			"quicksave": {k(engo.KeyF5)},
+			"quickload": {k(engo.KeyF9)},
+			"bindings":  {k(engo.KeyF1)},
+			"paths":     {k(engo.KeyF3)},
+		},
+		MouseSensitivity: 1,
+		Gamepad:          defaultGamepad(),
+	}
+}
+
+// ControlsConfigPath returns where the controls config file is kept: in the
+// user's config directory, or the working directory if there is none.
+func ControlsConfigPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "controls.json"
+	}
+	return filepath.Join(dir, "SkeleDoom", "controls.json")
+}
+
+// LoadControls reads the controls config at path. A missing file gives the
+// defaults; controls missing from the file keep their default bindings.
+func LoadControls(path string) (*ControlsConfig, error) {
+	c := DefaultControls()
+	b, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return c, nil
+	}
+	if err != nil {
+		return c, err
+	}
+	var file ControlsConfig
+	if err := json.Unmarshal(b, &file); err != nil {
+		return c, fmt.Errorf("%s: %w", path, err)
+	}
+	if file.Version != controlsVersion {
+		return c, fmt.Errorf("%s: unsupported controls version %d (want %d)", path, file.Version, controlsVersion)
+	}
+	for name, bindings := range file.Bindings {
+		c.Bindings[name] = bindings
+	}
+	if file.MouseSensitivity > 0 {
+		c.MouseSensitivity = file.MouseSensitivity
+	}
+	c.InvertMouse = file.InvertMouse
+
+	pad := file.Gamepad
+	for name, bindings := range pad.Bindings {
+		c.Gamepad.Bindings[name] = bindings
+	}
+	if pad.Deadzone > 0 && pad.Deadzone < 1 {
+		c.Gamepad.Deadzone = pad.Deadzone
+	}
+	if pad.Curve > 0 {
+		c.Gamepad.Curve = pad.Curve
+	}
+	if pad.LookSpeed > 0 {
+		c.Gamepad.LookSpeed = pad.LookSpeed
+	}
+	c.Gamepad.InvertLook = pad.InvertLook
+	return c, nil
+}
+
+// Save writes the config to path, creating its directory if needed.
+func (c *ControlsConfig) Save(path string) error {
+	b, err := json.MarshalIndent(c, "", "\t")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, b, 0o644)
+}
+
+// Apply registers every control's key bindings with engo.Input as a button
+// of the same name. Mouse bindings are checked by Down and JustPressed.
+// Call it again after changing the bindings.
+func (c *ControlsConfig) Apply() {
+	for _, ctl := range InputControls {
+		var keys []engo.Key
+		for _, b := range c.Bindings[ctl.Name] {
+			if !b.Mouse {
+				keys = append(keys, b.Key)
+			}
+		}
+		engo.Input.RegisterButton(ctl.Name, keys...)
+	}
+}
+
+// JustPressed reports whether control was pressed this frame, by a key or
+// by a mouse button.
+// Nothing is pressed while the bindings menu is open. A nil config only
+// checks keys.
+func (c *ControlsConfig) JustPressed(control string) bool {
+	if c != nil && c.suspended {
+		return false
+	}
+	if engo.Input.Button(control).JustPressed() {
+		return true
+	}
+	if c == nil || engo.Input.Mouse.Action != engo.Press {
+		return false
+	}
+	for _, b := range c.Bindings[control] {
+		if b.Mouse && b.Button == engo.Input.Mouse.Button {
+			return true
+		}
+	}
+	return false
+}
+
+// Down reports whether control is held, given which mouse buttons are. The
+// mouse's state is only reported as it changes, so callers that need held
+// mouse buttons track them with a MouseButtons.
+func (c *ControlsConfig) Down(control string, mouse *MouseButtons) bool {
+	if engo.Input.Button(control).Down() {
+		return true
+	}
+	for _, b := range c.Bindings[control] {
+		if b.Mouse && mouse.Down(b.Button) {
+			return true
+		}
+	}
+	return false
+}
+
+// BoundTo returns the control binding b is bound to, if any.
+func (c *ControlsConfig) BoundTo(b Binding) (string, bool) {
+	for _, ctl := range InputControls {
+		for _, bound := range c.Bindings[ctl.Name] {
+			if bound == b {
+				return ctl.Name, true
+			}
+		}
+	}
+	return "", false
+}
+
+// Unbind removes b from every control.
+func (c *ControlsConfig) Unbind(b Binding) {
+	for name, bindings := range c.Bindings {
+		kept := bindings[:0]
+		for _, bound := range bindings {
+			if bound != b {
+				kept = append(kept, bound)
+			}
+		}
+		c.Bindings[name] = kept
+	}
+}
+
+// MouseButtons tracks which mouse buttons are held, from the presses and
+// releases reported by engo.Input.Mouse. Update it once per frame.
+type MouseButtons struct {
+	held [engo.MouseButtonLast + 1]bool
+}
+
+func (m *MouseButtons) Update() {
+	b := engo.Input.Mouse.Button
+	if b < 0 || b > engo.MouseButtonLast {
+		return
+	}
+	switch engo.Input.Mouse.Action {
+	case engo.Press:
+		m.held[b] = true
+	case engo.Release:
+		m.held[b] = false
+	}
+}
+
+// Down reports whether b is held.
+func (m *MouseButtons) Down(b engo.MouseButton) bool {
+	return b >= 0 && b <= engo.MouseButtonLast && m.held[b]
+}
@@ -0,0 +1,386 @@
+package systems
+
+import (
+	"fmt"
+	"image/color"
+	"strings"
+
+	"github.com/EngoEngine/ecs"
+	"github.com/EngoEngine/engo"
+	"github.com/EngoEngine/engo/common"
+)
+
+const (
+	// Bindings menu layout (screen coordinates).
+	bindMenuX      float32 = 120
+	bindMenuY      float32 = 10
+	bindMenuW      float32 = 400
+	bindMenuValueX float32 = 260 // left edge of the bindings column
+	bindMenuRowH   float32 = 13
+
+	// Mouse sensitivity range and step on the bindings menu.
+	minSensitivity  float32 = 0.1
+	maxSensitivity  float32 = 5
+	sensitivityStep float32 = 0.1
+
+	// maxBindings is how many keys or buttons one control keeps; binding
+	// another pushes out the oldest.
+	maxBindings = 2
+
+	// menuControl is the control that opens this menu. Its last key can't
+	// be unbound or moved, so that the menu can't be locked out.
+	menuControl = "bindings"
+)
+
+// Extra bindings menu rows after the controls.
+const (
+	rowSensitivity = iota
+	rowInvert
+	rowDefaults
+	extraRows
+)
+
+// menuButtons are the fixed keys that drive the bindings menu. They can't be
+// rebound, so that the menu can't be locked out.
+var menuButtons = []struct {
+	name string
+	keys []engo.Key
+}{
+	{"menu-up", []engo.Key{engo.KeyArrowUp}},
+	{"menu-down", []engo.Key{engo.KeyArrowDown}},
+	{"menu-left", []engo.Key{engo.KeyArrowLeft}},
+	{"menu-right", []engo.Key{engo.KeyArrowRight}},
+	{"menu-select", []engo.Key{engo.KeyEnter, engo.KeyNumEnter}},
+	{"menu-clear", []engo.Key{engo.KeyBackspace, engo.KeyDelete}},
+	{"menu-back", []engo.Key{engo.KeyEscape}},
+}
+
+type bindMenuRow struct {
+	label, value *sprite
+	shown        string // label, value and selection currently drawn
+}
+
+// BindingsMenuSystem is the in-game controls screen. The "bindings" control
+// opens it over the paused game; the arrow keys pick a row, Enter rebinds
+// the control on it to the next key or mouse button pressed, Backspace
+// unbinds it, and Escape closes the menu, saving the changes to Path.
+// Binding something that is already bound to another control asks for it to
+// be pressed again before moving it over.
+//
+//	menu := &systems.BindingsMenuSystem{Controls: controls, Path: path, Tick: tick}
+//	w.AddSystem(menu)
+type BindingsMenuSystem struct {
+	// Controls is the configuration being edited.
+	Controls *ControlsConfig
+	// Path is where the configuration is saved when the menu closes.
+	Path string
+	// Tick is paused while the menu is open. May be nil.
+	Tick *TickSystem
+
+	font, selFont, dimFont *common.Font
+
+	bg          sprite
+	title       *sprite
+	status      *sprite
+	statusShown string
+	rows        []*bindMenuRow
+
+	open      bool
+	selected  int
+	capturing bool
+	conflict  *Binding // pressed once while bound to another control
+	refused   bool     // tried to take the menu's last key away
+	changed   bool
+}
+
+func (s *BindingsMenuSystem) New(w *ecs.World) {
+	if s.Controls == nil {
+		s.Controls = DefaultControls()
+	}
+	for _, b := range menuButtons {
+		engo.Input.RegisterButton(b.name, b.keys...)
+	}
+	// One button per key, so that the menu can tell which one is pressed.
+	for _, k := range keyNames {
+		engo.Input.RegisterButton("key:"+k.name, k.key)
+	}
+
+	s.font = newHUDFont(11, color.White)
+	s.selFont = newHUDFont(11, color.RGBA{0xFF, 0xD7, 0x00, 0xFF})
+	s.dimFont = newHUDFont(11, color.RGBA{0xAA, 0xAA, 0xAA, 0xFF})
+	if s.font == nil || s.selFont == nil || s.dimFont == nil {
+		return
+	}
+
+	s.bg = sprite{BasicEntity: ecs.NewBasic()}
+	s.bg.SpaceComponent = common.SpaceComponent{
+		Position: engo.Point{X: bindMenuX - 10, Y: bindMenuY - 6},
+		Width:    bindMenuW,
+		Height:   float32(len(InputControls)+extraRows+3)*bindMenuRowH + 12,
+	}
+	s.bg.RenderComponent = common.RenderComponent{
+		Drawable:    common.Rectangle{},
+		Color:       color.RGBA{0x10, 0x10, 0x10, 0xE0},
+		StartZIndex: 70, // above everything else on the HUD
+		Hidden:      true,
+	}
+	s.bg.SetShader(common.LegacyHUDShader)
+	w.AddEntity(&s.bg)
+
+	text := func(x, y float32, font *common.Font) *sprite {
+		t := &sprite{BasicEntity: ecs.NewBasic()}
+		t.Position = engo.Point{X: x, Y: y}
+		t.RenderComponent = common.RenderComponent{
+			Drawable:    common.Text{Font: font},
+			StartZIndex: 71,
+			Hidden:      true,
+		}
+		t.SetShader(common.HUDShader)
+		w.AddEntity(t)
+		return t
+	}
+
+	s.title = text(bindMenuX, bindMenuY, s.selFont)
+	s.title.Drawable = common.Text{Font: s.selFont, Text: "CONTROLS"}
+	for i := 0; i < len(InputControls)+extraRows; i++ {
+		y := bindMenuY + float32(i+1)*bindMenuRowH
+		row := &bindMenuRow{
+			label: text(bindMenuX, y, s.font),
+			value: text(bindMenuValueX, y, s.font),
+		}
+		s.rows = append(s.rows, row)
+	}
+	s.status = text(bindMenuX, bindMenuY+float32(len(s.rows)+1)*bindMenuRowH+4, s.dimFont)
+}
+
+func (s *BindingsMenuSystem) Remove(basic ecs.BasicEntity) {}
+
+func (s *BindingsMenuSystem) Update(dt float32) {
+	if s.rows == nil {
+		return
+	}
+	if !s.open {
+		if s.Controls.JustPressed("bindings") {
+			s.setOpen(true)
+		}
+		return
+	}
+
+	switch {
+	case s.capturing:
+		s.capture()
+	case engo.Input.Button("menu-back").JustPressed(), engo.Input.Button("bindings").JustPressed():
+		s.setOpen(false)
+		return
+	case engo.Input.Button("menu-up").JustPressed():
+		s.selected = (s.selected + len(s.rows) - 1) % len(s.rows)
+		s.refused = false
+	case engo.Input.Button("menu-down").JustPressed():
+		s.selected = (s.selected + 1) % len(s.rows)
+		s.refused = false
+	case engo.Input.Button("menu-select").JustPressed():
+		s.activate(0)
+	case engo.Input.Button("menu-left").JustPressed():
+		s.activate(-1)
+	case engo.Input.Button("menu-right").JustPressed():
+		s.activate(1)
+	case engo.Input.Button("menu-clear").JustPressed():
+		if s.selected < len(InputControls) {
+			if name := InputControls[s.selected].Name; name == menuControl {
+				s.refused = true
+			} else {
+				s.Controls.Bindings[name] = nil
+				s.changed = true
+			}
+		}
+	}
+	s.draw()
+}
+
+func (s *BindingsMenuSystem) setOpen(open bool) {
+	s.open = open
+	s.capturing = false
+	s.conflict = nil
+	s.refused = false
+	s.Controls.suspended = open
+	if s.Tick != nil {
+		s.Tick.Paused = open
+	}
+	s.bg.Hidden = !open
+	s.title.Hidden = !open
+	s.status.Hidden = !open
+	for _, row := range s.rows {
+		row.label.Hidden = !open
+		row.value.Hidden = !open
+	}
+	if open {
+		s.draw()
+		return
+	}
+
+	if !s.changed {
+		return
+	}
+	s.changed = false
+	s.Controls.Apply()
+	if s.Path == "" {
+		return
+	}
+	if err := s.Controls.Save(s.Path); err != nil {
+		println("Warning: could not save controls:", err.Error())
+		engo.Mailbox.Dispatch(HUDMessage{Text: "Could not save controls"})
+		return
+	}
+	engo.Mailbox.Dispatch(HUDMessage{Text: "Controls saved"})
+}
+
+// activate acts on the selected row: dir is 0 for Enter, and -1 or 1 for the
+// left and right arrows.
+func (s *BindingsMenuSystem) activate(dir int) {
+	c := s.Controls
+	switch s.selected - len(InputControls) {
+	case rowSensitivity:
+		if dir == 0 {
+			return
+		}
+		c.MouseSensitivity += float32(dir) * sensitivityStep
+		if c.MouseSensitivity < minSensitivity {
+			c.MouseSensitivity = minSensitivity
+		}
+		if c.MouseSensitivity > maxSensitivity {
+			c.MouseSensitivity = maxSensitivity
+		}
+	case rowInvert:
+		c.InvertMouse = !c.InvertMouse
+	case rowDefaults:
+		if dir != 0 {
+			return
+		}
+		d := DefaultControls()
+		c.Bindings, c.MouseSensitivity, c.InvertMouse = d.Bindings, d.MouseSensitivity, d.InvertMouse
+	default:
+		if dir != 0 {
+			return
+		}
+		s.capturing = true
+	}
+	s.changed = true
+}
+
+// capture waits for the key or mouse button to bind to the selected control.
+func (s *BindingsMenuSystem) capture() {
+	if engo.Input.Button("menu-back").JustPressed() {
+		s.capturing = false
+		s.conflict = nil
+		return
+	}
+	b, ok := pressedBinding()
+	if !ok {
+		return
+	}
+
+	c := s.Controls
+	name := InputControls[s.selected].Name
+	if other, bound := c.BoundTo(b); bound && other != name {
+		if other == menuControl && len(c.Bindings[menuControl]) == 1 {
+			s.refused = true
+			return
+		}
+		if s.conflict == nil || *s.conflict != b {
+			// Ask first; pressing the same key again confirms.
+			s.conflict = &b
+			return
+		}
+	}
+	c.Unbind(b)
+	bindings := append([]Binding{b}, c.Bindings[name]...)
+	if len(bindings) > maxBindings {
+		bindings = bindings[:maxBindings]
+	}
+	c.Bindings[name] = bindings
+	s.capturing = false
+	s.conflict = nil
+	s.refused = false
+}
+
+// pressedBinding returns the key or mouse button pressed this frame, if any.
+func pressedBinding() (Binding, bool) {
+	if engo.Input.Mouse.Action == engo.Press {
+		return MouseBinding(engo.Input.Mouse.Button), true
+	}
+	for _, k := range keyNames {
+		if engo.Input.Button("key:" + k.name).JustPressed() {
+			return KeyBinding(k.key), true
+		}
+	}
+	return Binding{}, false
+}
+
+func (s *BindingsMenuSystem) draw() {
+	c := s.Controls
+	for i, row := range s.rows {
+		var label, value string
+		switch i - len(InputControls) {
+		case rowSensitivity:
+			label, value = "Mouse sensitivity", fmt.Sprintf("< %.1f >", c.MouseSensitivity)
+		case rowInvert:
+			label, value = "Invert mouse", "no"
+			if c.InvertMouse {
+				value = "yes"
+			}
+		case rowDefaults:
+			label = "Reset to defaults"
+		default:
+			ctl := InputControls[i]
+			label, value = ctl.Label, bindingList(c.Bindings[ctl.Name])
+			if s.capturing && i == s.selected {
+				value = "press a key or button..."
+			}
+		}
+
+		font := s.font
+		if i == s.selected {
+			font = s.selFont
+		}
+		if shown := label + "\x00" + value + "\x00" + fmt.Sprint(i == s.selected); shown != row.shown {
+			row.label.Drawable = common.Text{Font: font, Text: label}
+			row.value.Drawable = common.Text{Font: font, Text: value}
+			row.shown = shown
+		}
+	}
+
+	status := "Arrows: choose   Enter: bind   Backspace: unbind   Esc: close"
+	switch {
+	case s.refused:
+		status = fmt.Sprintf("The %s can't be left without a key.", controlLabel(menuControl))
+	case s.conflict != nil:
+		other, _ := c.BoundTo(*s.conflict)
+		status = fmt.Sprintf("%s is bound to %s. Press it again to move it, or Esc.", s.conflict, controlLabel(other))
+	case s.capturing:
+		status = "Press the new key or mouse button, or Esc to cancel"
+	}
+	if status != s.statusShown {
+		s.status.Drawable = common.Text{Font: s.dimFont, Text: status}
+		s.statusShown = status
+	}
+}
+
+func bindingList(bindings []Binding) string {
+	if len(bindings) == 0 {
+		return "(unbound)"
+	}
+	names := make([]string, len(bindings))
+	for i, b := range bindings {
+		names[i] = b.String()
+	}
+	return strings.Join(names, ", ")
+}
+
+func controlLabel(name string) string {
+	for _, ctl := range InputControls {
+		if ctl.Name == name {
+			return ctl.Label
+		}
+	}
+	return name
+}
@@ -0,0 +1,544 @@
+package systems
+
+import (
+	"image/color"
+
+	"github.com/EngoEngine/ecs"
+	"github.com/EngoEngine/engo"
+	"github.com/EngoEngine/engo/common"
+	"github.com/EngoEngine/engo/math"
+	"github.com/EngoEngine/gl"
+	"github.com/SkeleboyStudios/SkeleDoom/shaders"
+)
+
+const (
+	// Throwing.
+	bombSpeed       float32 = 200 // horizontal speed, world units per second
+	bombLift        float32 = 120 // upward speed, world units per second
+	bombThrowHeight float32 = 30  // how far above the thrower's feet it leaves the hand
+	bombThrowDelay  float32 = 0.6 // seconds between throws
+
+	// Flight.
+	bombGravity  float32 = 300 // world units per second²
+	bombBounce   float32 = 0.5 // fraction of its speed kept by each bounce
+	bombSettle   float32 = 40  // slower landings than this don't bounce
+	bombFriction float32 = 3   // rolling friction; see applyFriction
+	bombStop     float32 = 20
+	bombSize     float32 = 8 // billboard width and height
+
+	// Defaults for unset BombComponent fields.
+	bombFuse      float32 = 2   // seconds
+	bombDamage    float32 = 80  // at the centre of the blast
+	bombRadius    float32 = 90  // world units
+	bombKnockback float32 = 400 // world units per second, at the centre
+
+	// The blast shakes the view by up to bombShake screen units for
+	// bombShakeTime seconds, and flashes the screen for bombFlashTime, both
+	// fading with distance out to bombFeel world units.
+	bombShake     float32 = 8
+	bombShakeTime float32 = 0.6
+	bombFlashTime float32 = 0.35
+	bombFeel      float32 = 400
+
+	// bombNoise is how far the blast is heard.
+	bombNoise float32 = 500
+)
+
+// BombComponent is a thrown bomb. It flies in an arc, bouncing off walls and
+// the floor, until its Fuse burns down, then explodes: everything within
+// Radius that isn't behind a wall is hurt and knocked back, less the further
+// away it is. The bomb's SpaceComponent Position is in wall world-space, and
+// its Height is how high it is above the level's base floor, so that
+// TickSystem smooths its arc like its Position.
+type BombComponent struct {
+	// Velocity is the horizontal velocity in world units per second, and
+	// Climb the upward one.
+	Velocity engo.Point
+	Climb    float32
+	// Fuse is the seconds left until it explodes. It is initialised to
+	// bombFuse by BombSystem when zero.
+	Fuse float32
+	// Damage is dealt at the centre of the blast, falling off to nothing at
+	// Radius. Knockback falls off alike. They are initialised to bombDamage,
+	// bombRadius and bombKnockback when zero.
+	Damage, Radius, Knockback float32
+	// Owner is the ID of the entity that threw it. Its blast hurts everyone,
+	// the Owner included.
+	Owner uint64
+}
+
+func (c *BombComponent) GetBombComponent() *BombComponent { return c }
+
+// BombFace is satisfied by anything that embeds *BombComponent.
+type BombFace interface {
+	GetBombComponent() *BombComponent
+}
+
+// BombAble is the interface AddByInterface uses to detect bombs.
+type BombAble interface {
+	common.BasicFace
+	common.SpaceFace
+	BombFace
+}
+
+// BomberAble is the interface AddByInterface uses to detect entities that
+// throw bombs from their inventory.
+type BomberAble interface {
+	common.BasicFace
+	common.SpaceFace
+	ControlFace
+	InputFace
+	InventoryFace
+}
+
+// bombEntity is the system's internal representation of one bomb.
+type bombEntity struct {
+	*ecs.BasicEntity
+	*common.SpaceComponent
+	*BombComponent
+	*InterpolatedComponent
+
+	// billboard is the 3D view entity; uses shaders.ViewShader.
+	billboard sprite
+	// mapDot is the minimap square; uses shaders.MinimapShader.
+	mapDot sprite
+}
+
+type bomberEntity struct {
+	*ecs.BasicEntity
+	*common.SpaceComponent
+	*ControlComponent
+	*InputComponent
+	*InventoryComponent
+
+	cooldown float32 // seconds until the next throw
+}
+
+// BombSystem throws bombs from the inventory of each bomber on its Throw
+// command, flies them and sets them off. It accepts WallMapAble entities,
+// which bombs bounce off and which shield whatever is behind them from the
+// blast, and blasts damage those with a DestructibleComponent. It accepts
+// the player (ViewPlayerAble) and EnemyAble entities as the blast's
+// targets. A blast near the player shakes the view and flashes the screen.
+//
+//	bombs := &systems.BombSystem{Floors: floorSystem, Tex: bombTex}
+//	var bomberable      *systems.BomberAble
+//	var bombable        *systems.BombAble
+//	var bombplayerable  *systems.ViewPlayerAble
+//	var bombwallable    *systems.WallMapAble
+//	var bombenemyable   *systems.EnemyAble
+//	w.AddSystemInterface(bombs, []any{bomberable, bombable, bombplayerable, bombwallable, bombenemyable}, nil)
+type BombSystem struct {
+	// Floors is what bombs land on. Without it they land on the base floor.
+	Floors *FloorSystem
+	// Tex is the bombs' billboard texture. Nil draws them as dark squares.
+	Tex *gl.Texture
+
+	w             *ecs.World
+	playerID      uint64
+	player        *common.SpaceComponent
+	playerControl *ControlComponent
+	bombers       []*bomberEntity
+	bombs         []*bombEntity
+	walls         []projectileWallEntity
+	enemies       []enemyTarget
+
+	flash     sprite
+	strength  float32 // how hard the latest blast was felt, from 0 to 1
+	shakeTime float32 // seconds left of shaking the view
+	flashTime float32 // seconds left of the flash
+	shakeAge  float32 // seconds the view has been shaking for
+}
+
+func (s *BombSystem) New(w *ecs.World) {
+	s.w = w
+
+	s.flash = sprite{BasicEntity: ecs.NewBasic()}
+	s.flash.SpaceComponent = common.SpaceComponent{
+		Width:  engo.GameWidth(),
+		Height: engo.GameHeight(),
+	}
+	s.flash.RenderComponent = common.RenderComponent{
+		Drawable:    common.Rectangle{},
+		Color:       color.RGBA{},
+		StartZIndex: 55, // with the teleport flash, below HUD text
+	}
+	s.flash.SetShader(common.LegacyHUDShader)
+	w.AddEntity(&s.flash)
+}
+
+func (s *BombSystem) AddByInterface(i ecs.Identifier) {
+	if o, ok := i.(BomberAble); ok {
+		s.bombers = append(s.bombers, &bomberEntity{
+			BasicEntity:        o.GetBasicEntity(),
+			SpaceComponent:     o.GetSpaceComponent(),
+			ControlComponent:   o.GetControlComponent(),
+			InputComponent:     o.GetInputComponent(),
+			InventoryComponent: o.GetInventoryComponent(),
+		})
+	}
+	if o, ok := i.(ViewPlayerAble); ok {
+		s.playerID = o.GetBasicEntity().ID()
+		s.player = o.GetSpaceComponent()
+		s.playerControl = o.GetControlComponent()
+		return
+	}
+	if o, ok := i.(EnemyAble); ok {
+		s.enemies = append(s.enemies, enemyTarget{o.GetBasicEntity(), o.GetSpaceComponent(), o.GetEnemyComponent()})
+		return
+	}
+	// MapSystem's minimap entities embed a nil WallMapComponent, so skip
+	// anything without real geometry.
+	if o, ok := i.(WallMapAble); ok {
+		if o.GetWallMapComponent() == nil {
+			return
+		}
+		wall := projectileWallEntity{
+			BasicEntity:      o.GetBasicEntity(),
+			WallMapComponent: o.GetWallMapComponent(),
+		}
+		if d, ok := i.(DestructibleFace); ok {
+			wall.destructible = d.GetDestructibleComponent()
+		}
+		s.walls = append(s.walls, wall)
+		return
+	}
+
+	o, ok := i.(BombAble)
+	if !ok {
+		return
+	}
+	bc := o.GetBombComponent()
+	if bc.Fuse == 0 {
+		bc.Fuse = bombFuse
+	}
+	if bc.Damage == 0 {
+		bc.Damage = bombDamage
+	}
+	if bc.Radius == 0 {
+		bc.Radius = bombRadius
+	}
+	if bc.Knockback == 0 {
+		bc.Knockback = bombKnockback
+	}
+	b := &bombEntity{
+		BasicEntity:    o.GetBasicEntity(),
+		SpaceComponent: o.GetSpaceComponent(),
+		BombComponent:  bc,
+	}
+
+	b.billboard = sprite{BasicEntity: ecs.NewBasic()}
+	b.billboard.RenderComponent = common.RenderComponent{
+		Drawable: b.drawable(s.Tex),
+		Color:    color.RGBA{0xff, 0xff, 0xff, 0xff},
+	}
+	if s.Tex == nil {
+		b.billboard.Color = color.RGBA{0x22, 0x22, 0x22, 0xff}
+	}
+	b.billboard.SetShader(shaders.ViewShader)
+	s.w.AddEntity(&b.billboard)
+
+	const dotSize float32 = 3
+	b.mapDot = sprite{BasicEntity: ecs.NewBasic()}
+	b.mapDot.Width, b.mapDot.Height = dotSize, dotSize
+	b.mapDot.RenderComponent = common.RenderComponent{
+		Drawable:    common.Rectangle{},
+		Color:       color.RGBA{0xff, 0xaa, 0x00, 0xff},
+		StartZIndex: 4,
+	}
+	b.mapDot.SetShader(shaders.MinimapShader)
+	s.w.AddEntity(&b.mapDot)
+
+	s.bombs = append(s.bombs, b)
+}
+
+// Remove despawns a bomb's billboard and minimap dot along with it.
+func (s *BombSystem) Remove(basic ecs.BasicEntity) {
+	if s.player != nil && s.playerID == basic.ID() {
+		s.player = nil
+		s.playerControl = nil
+	}
+	for i, b := range s.bombers {
+		if b.ID() == basic.ID() {
+			s.bombers = append(s.bombers[:i], s.bombers[i+1:]...)
+			return
+		}
+	}
+	for i, b := range s.bombs {
+		if b.ID() == basic.ID() {
+			despawn(s.w, b.billboard.BasicEntity, &b.billboard.RenderComponent)
+			despawn(s.w, b.mapDot.BasicEntity, &b.mapDot.RenderComponent)
+			s.bombs = append(s.bombs[:i], s.bombs[i+1:]...)
+			return
+		}
+	}
+	for i, wall := range s.walls {
+		if wall.ID() == basic.ID() {
+			s.walls = append(s.walls[:i], s.walls[i+1:]...)
+			return
+		}
+	}
+	for i, e := range s.enemies {
+		if e.ID() == basic.ID() {
+			s.enemies = append(s.enemies[:i], s.enemies[i+1:]...)
+			return
+		}
+	}
+}
+
+// Tick throws new bombs, flies the rest and sets off those whose fuse has
+// burnt down.
+func (s *BombSystem) Tick(dt float32) {
+	for _, b := range s.bombers {
+		if b.cooldown > 0 {
+			b.cooldown -= dt
+			continue
+		}
+		if !b.Command.Throw {
+			continue
+		}
+		if !b.takeBomb() {
+			engo.Mailbox.Dispatch(HUDMessage{Text: "No bombs"})
+			continue
+		}
+		b.cooldown = bombThrowDelay
+		s.throw(b)
+	}
+
+	// Blasts may remove bombs, walls and enemies, so go through a copy.
+	for _, b := range append([]*bombEntity(nil), s.bombs...) {
+		b.Fuse -= dt
+		if b.Fuse <= 0 {
+			s.explode(b)
+			continue
+		}
+		s.fly(b, dt)
+	}
+}
+
+// throw sends a bomb out of b's hand the way it is facing, carried along by
+// its own momentum.
+func (s *BombSystem) throw(b *bomberEntity) {
+	sin, cos := math.Sincos(b.Rotation * math.Pi / 180)
+	s.spawn(groundPos(b.Position), b.elevation+bombThrowHeight, BombComponent{
+		Velocity: engo.Point{X: sin*bombSpeed + b.velocity.X, Y: -cos*bombSpeed + b.velocity.Y},
+		Climb:    bombLift,
+		Owner:    b.ID(),
+	})
+}
+
+// spawn adds a bomb to the world, at pos in wall world-space and high above
+// the base floor.
+func (s *BombSystem) spawn(pos engo.Point, high float32, bc BombComponent) {
+	b := &bombEntity{
+		BasicEntity: new(ecs.BasicEntity),
+		SpaceComponent: &common.SpaceComponent{
+			Position: pos,
+			Width:    bombSize,
+			Height:   high,
+		},
+		BombComponent:         &bc,
+		InterpolatedComponent: &InterpolatedComponent{},
+	}
+	*b.BasicEntity = ecs.NewBasic()
+	s.w.AddEntity(b)
+}
+
+// fly moves b along its arc for one tick, bouncing it off any wall in the
+// way and off the floor.
+func (s *BombSystem) fly(b *bombEntity, dt float32) {
+	from := b.Position
+	to := engo.Point{X: from.X + b.Velocity.X*dt, Y: from.Y + b.Velocity.Y*dt}
+	if wall := s.nearestWall(engo.Line{P1: from, P2: to}); wall >= 0 {
+		// Stay put this tick, heading back off the wall.
+		n := s.walls[wall].Wall.Normal()
+		d := b.Velocity.X*n.X + b.Velocity.Y*n.Y
+		b.Velocity.X -= 2 * d * n.X
+		b.Velocity.Y -= 2 * d * n.Y
+		b.Velocity.MultiplyScalar(bombBounce)
+	} else {
+		b.Position = to
+	}
+
+	ground := float32(0)
+	if s.Floors != nil {
+		ground = s.Floors.GroundAt(b.Position)
+	}
+	b.Climb -= bombGravity * dt
+	b.Height += b.Climb * dt
+	if b.Height > ground {
+		return
+	}
+	b.Height = ground
+	if b.Climb < -bombSettle {
+		b.Climb = -b.Climb * bombBounce
+		b.Velocity.MultiplyScalar(bombBounce)
+		return
+	}
+	b.Climb = 0
+	applyFriction(&b.Velocity, bombFriction, bombStop, dt)
+}
+
+// nearestWall returns the index of the nearest solid wall path crosses, or
+// -1 if there is none.
+func (s *BombSystem) nearestWall(path engo.Line) int {
+	hit := -1
+	var best float32
+	for i, wall := range s.walls {
+		if wall.Passable {
+			continue
+		}
+		p, ok := engo.LineIntersection(path, wall.Wall)
+		if !ok {
+			continue
+		}
+		if d := path.P1.PointDistanceSquared(p); hit < 0 || d < best {
+			hit, best = i, d
+		}
+	}
+	return hit
+}
+
+// shielded reports whether a solid wall other than wall skip stands between
+// from and to.
+func (s *BombSystem) shielded(from, to engo.Point, skip int) bool {
+	path := engo.Line{P1: from, P2: to}
+	for i, wall := range s.walls {
+		if i == skip || wall.Passable {
+			continue
+		}
+		if _, ok := engo.LineIntersection(path, wall.Wall); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// explode sets b off: everyone within its Radius that no wall shields takes
+// damage and knockback falling off with distance, and so do destructible
+// walls. The blast is heard far and wide, and felt by a nearby player.
+func (s *BombSystem) explode(b *bombEntity) {
+	pos := b.Position
+	s.w.RemoveEntity(*b.BasicEntity)
+
+	// falloff is the share of the blast felt d away from it.
+	falloff := func(d float32) float32 { return 1 - d/b.Radius }
+	blast := func(d float32) Damage {
+		return Damage{
+			Amount:      b.Damage * falloff(d),
+			Type:        DamageExplosion,
+			Source:      b.Owner,
+			From:        pos,
+			Directional: true,
+			Knockback:   b.Knockback * falloff(d),
+		}
+	}
+
+	// Work out who is hit before dealing any damage, which may remove
+	// enemies and walls.
+	type hit struct {
+		id      uint64
+		health  *float32
+		defense *Defense
+		d       float32
+	}
+	var hits []hit
+	if s.playerControl != nil {
+		p := groundPos(s.player.Position)
+		if d := p.PointDistance(pos); d < b.Radius && !s.shielded(pos, p, -1) {
+			hits = append(hits, hit{s.playerID, &s.playerControl.Health, &s.playerControl.Defense, d})
+		}
+	}
+	for _, e := range s.enemies {
+		if d := e.Position.PointDistance(pos); d < b.Radius && !s.shielded(pos, e.Position, -1) {
+			hits = append(hits, hit{e.ID(), &e.Health, &e.Defense, d})
+		}
+	}
+	type wallHit struct {
+		wall   projectileWallEntity
+		damage float32
+	}
+	var walls []wallHit
+	for i, wall := range s.walls {
+		if wall.destructible == nil || wall.Passable {
+			continue
+		}
+		near := nearestPoint(pos, wall.Wall)
+		if d := near.PointDistance(pos); d < b.Radius && !s.shielded(pos, near, i) {
+			walls = append(walls, wallHit{wall, b.Damage * falloff(d)})
+		}
+	}
+
+	for _, h := range hits {
+		Deal(h.id, h.health, h.defense, blast(h.d))
+	}
+	for _, h := range walls {
+		if h.wall.destructible.Damage(h.damage) {
+			s.w.RemoveEntity(*h.wall.BasicEntity)
+		}
+	}
+
+	engo.Mailbox.Dispatch(NoiseMessage{Pos: pos, Radius: bombNoise})
+	if s.player != nil {
+		// A blast felt less than what is left of the last one's shake
+		// doesn't cut it short.
+		p := groundPos(s.player.Position)
+		felt := 1 - p.PointDistance(pos)/bombFeel
+		if felt > 0 && felt >= s.strength*s.shakeTime/bombShakeTime {
+			s.strength = felt
+			s.shakeTime, s.flashTime, s.shakeAge = bombShakeTime, bombFlashTime, 0
+		}
+	}
+}
+
+// drawable returns b's billboard, standing as high off the floor as b is.
+func (b *bombEntity) drawable(tex *gl.Texture) shaders.Billboard {
+	return shaders.Billboard{
+		Pos: b.Position,
+		W:   bombSize,
+		H:   bombSize,
+		Tex: tex,
+		Z:   defaultWallHeight - bombSize - b.Height,
+	}
+}
+
+// Update places the bombs' billboards and map dots where they are drawn this
+// frame, and shakes and flashes the screen after a nearby blast.
+func (s *BombSystem) Update(dt float32) {
+	if s.player != nil {
+		const near float32 = 1.0
+		eye := groundPos(s.player.Position)
+		sin, cos := math.Sincos(s.player.Rotation * math.Pi / 180)
+		for _, b := range s.bombs {
+			b.billboard.Drawable = b.drawable(s.Tex)
+			b.mapDot.Position = engo.Point{
+				X: b.Position.X + MapWallOffsetX - b.mapDot.Width/2,
+				Y: b.Position.Y + MapWallOffsetY - b.mapDot.Height/2,
+			}
+
+			// Depth-sort against the walls, as ProjectileSystem does.
+			relX := b.Position.X - eye.X
+			relY := -b.Position.Y + eye.Y
+			camY := relY*cos + relX*sin
+			b.billboard.Hidden = camY < near
+			if !b.billboard.Hidden {
+				b.billboard.SetZIndex(-(camY + 50))
+			}
+		}
+	}
+
+	// ── Shake and flash ───────────────────────────────────────────────────
+	var shake engo.Point
+	if s.shakeTime > 0 {
+		s.shakeTime -= dt
+		s.shakeAge += dt
+		amp := bombShake * s.strength * math.Max(s.shakeTime, 0) / bombShakeTime
+		shake = engo.Point{X: amp * math.Sin(s.shakeAge*90), Y: amp * math.Cos(s.shakeAge*71)}
+	}
+	shaders.ViewShader.Shake(shake)
+	if s.flashTime > 0 {
+		s.flashTime -= dt
+	}
+	t := math.Max(s.flashTime, 0) / bombFlashTime
+	s.flash.Color = color.RGBA{0xFF, 0xCC, 0x66, uint8(0xCC * s.strength * t)}
+}
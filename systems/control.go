@@ -7,6 +7,8 @@ import (
 	"github.com/EngoEngine/engo"
 	"github.com/EngoEngine/engo/common"
 	"github.com/EngoEngine/engo/math"
+
+	"github.com/SkeleboyStudios/SkeleDoom/shaders"
 )
 
 const (
@@ -16,6 +18,16 @@ const (
 	healthBarW float32 = 300
 	healthBarH float32 = 10
 
+	// The armor bar is a thin strip under the health bar, full at
+	// armorBarMax.
+	armorBarY   float32 = 196
+	armorBarH   float32 = 3
+	armorBarMax float32 = 100
+
+	// invulnerableFlashRate is how many times a second the health bar
+	// blinks during invulnerability frames.
+	invulnerableFlashRate float32 = 15
+
 	// Stamina bar HUD position and dimensions (screen coordinates).
 	staminaBarX float32 = 10
 	staminaBarY float32 = 200
@@ -23,13 +35,21 @@ const (
 	staminaBarH float32 = 10
 
 	// Stamina rates in units per second.
-	staminaDrainRate float32 = 20 // drained per second while sprinting
 	staminaRegenRate float32 = 8  // regenerated per second while not sprinting
 	staminaResumeAt  float32 = 50 // stamina level at which exhaustion clears
 
-	// Speed multipliers applied on top of the entity's base Speed.
-	sprintMultiplier float32 = 2.0
-	crouchSpeedMul   float32 = 0.5
+	// staminaFlashTime is how long the stamina bar flashes after an action is
+	// refused for lack of stamina, and staminaFlashRate how many times a
+	// second it blinks.
+	staminaFlashTime float32 = 0.6
+	staminaFlashRate float32 = 10
+
+	// The damage indicator is a bar hurtMarkRadius from the middle of the
+	// screen, on the side hits came from, shown for hurtMarkTime seconds.
+	hurtMarkTime   float32 = 1
+	hurtMarkRadius float32 = 70
+	hurtMarkW      float32 = 60
+	hurtMarkH      float32 = 6
 
 	// Crouch adds this fraction of NormalHeight to raise the Height value,
 	// which lowers the camera view (higher Height = floor rises = view goes down).
@@ -43,13 +63,91 @@ const (
 
 	// Shooting mechanics.
 	shootCooldown float32 = 0.25 // minimum time between shots (seconds)
+
+	// maxTurnPerTick is the most an entity turns in one tick, in degrees.
+	// Turning beyond it is carried over to the following ticks.
+	maxTurnPerTick float32 = 5
 )
 
+// MovementConfig tunes how an entity speeds up, slows down and steers.
+// Speeds are relative to ControlComponent.Speed, so speed boosts scale them
+// all.
+type MovementConfig struct {
+	// Accel is how fast the entity reaches its target speed on the ground,
+	// as the fraction of that speed gained per second.
+	Accel float32
+	// Friction slows the entity on the ground, as the fraction of its speed
+	// lost per second.
+	Friction float32
+	// StopSpeed is the speed below which friction works as if the entity were
+	// moving at StopSpeed, so that it comes to a clean stop rather than
+	// creeping.
+	StopSpeed float32
+	// AirControl scales Accel while airborne, from 0 (no steering in the air)
+	// to 1 (as on the ground). Friction doesn't act in the air, so jumps keep
+	// their momentum.
+	AirControl float32
+	// SprintMul and CrouchMul scale Speed to give the sprinting and
+	// crouching top speeds.
+	SprintMul, CrouchMul float32
+
+	// DashMul scales Speed to give the dash speed, held for DashTime
+	// seconds. Another dash can't start until DashCooldown seconds after the
+	// last one began.
+	DashMul, DashTime, DashCooldown float32
+
+	// StepHeight is the highest rise in the floor the entity walks up
+	// without jumping, and the deepest drop it walks down without falling.
+	// Higher rises block it.
+	StepHeight float32
+	// FallSafe is how far the entity can fall unhurt, in world units. Each
+	// unit fallen beyond it costs FallDamage health.
+	FallSafe, FallDamage float32
+}
+
+// DefaultMovement returns the standard movement tuning.
+func DefaultMovement() MovementConfig {
+	return MovementConfig{
+		Accel:      10,
+		Friction:   6,
+		StopSpeed:  40,
+		AirControl: 0.15,
+		SprintMul:  2,
+		CrouchMul:  0.5,
+
+		DashMul:      4,
+		DashTime:     0.15,
+		DashCooldown: 1,
+
+		StepHeight: 3,
+		FallSafe:   20,
+		FallDamage: 2,
+	}
+}
+
+// StaminaCosts is the stamina each action costs, keyed by the action's
+// control name. "sprint" is drained per second while sprinting; the others
+// are paid once per use. Actions left out cost nothing.
+type StaminaCosts map[string]float32
+
+// DefaultStaminaCosts returns the standard stamina costs.
+func DefaultStaminaCosts() StaminaCosts {
+	return StaminaCosts{
+		"sprint": 20,
+		"jump":   10,
+		"dash":   25,
+		"melee":  15,
+	}
+}
+
 // ControlComponent holds movement parameters and runtime state for a
 // player-controlled entity.
 type ControlComponent struct {
-	// Speed is the base translation speed in world-units per second.
+	// Speed is the top walking speed in world-units per second.
 	Speed float32
+	// Movement tunes acceleration, friction and air control. It is
+	// initialised to DefaultMovement by ControlSystem.Add when left zero.
+	Movement MovementConfig
 	// RotSpeed is the rotation speed in degrees per second (mouse axis).
 	RotSpeed float32
 
@@ -57,24 +155,72 @@ type ControlComponent struct {
 	// It is initialised to 100 by ControlSystem.Add when the value is zero.
 	Health float32
 
+	// Defense is what protects Health from Damage.
+	Defense Defense
+
 	// Stamina is the sprint resource in the range [0, 100].
 	// It is initialised to 100 by ControlSystem.Add when the value is zero.
 	Stamina float32
+	// StaminaCosts is what sprinting, jumping and dashing cost. It is
+	// initialised to DefaultStaminaCosts by ControlSystem.Add when nil.
+	StaminaCosts StaminaCosts
 
 	// NormalHeight is captured from SpaceComponent.Height on the very first
 	// Update tick.  Crouch and jump are expressed relative to this value.
 	NormalHeight float32
 
-	// unexported runtime state
-	exhausted    bool    // true when stamina hit 0; cleared when Stamina >= staminaResumeAt
-	isJumping    bool    // true while the player is airborne
-	jumpVelocity float32 // current velocity magnitude; positive decreases Height (view goes up)
+	// Floor is the height of the ground under the player above the level's
+	// base floor. ControlSystem looks it up in its Floors every tick, and the
+	// eye rides up and down with it.
+	Floor float32
 
-	velocity engo.Point // current horizontal movement vector (world-units/frame)
+	// unexported runtime state
+	exhausted    bool       // true when stamina hit 0; cleared when Stamina >= staminaResumeAt
+	isJumping    bool       // true while the player is airborne
+	jumpVelocity float32    // current velocity magnitude; positive decreases Height (view goes up)
+	turnLeft     float32    // degrees of turning carried over to the next tick
+	elevation    float32    // height of the feet above the base floor
+	fallFrom     float32    // highest elevation reached since leaving the ground
+	wantedSprint bool       // Sprint of the previous command, to flash only on a new press
+	staminaFlash float32    // seconds left of flashing the stamina bar
+	dashTime     float32    // seconds left of the current dash
+	dashCooldown float32    // seconds until the next dash may start
+	stride       float32    // seconds until the next loud footstep
+	hurtFrom     engo.Point // where the last hit came from, in wall world-space
+	hurtTime     float32    // seconds left of showing the damage indicator
+
+	velocity engo.Point // current horizontal velocity in world space (world-units/sec)
 }
 
 func (c *ControlComponent) GetControlComponent() *ControlComponent { return c }
 
+// spendStamina pays the cost of a one-shot action, reporting whether there
+// was enough stamina for it. A refused action flashes the stamina bar.
+// Running out of stamina exhausts the entity just as sprinting does.
+func (c *ControlComponent) spendStamina(action string) bool {
+	cost := c.StaminaCosts[action]
+	if cost <= 0 {
+		return true
+	}
+	if c.exhausted || c.Stamina < cost {
+		c.staminaFlash = staminaFlashTime
+		return false
+	}
+	c.Stamina -= cost
+	if c.Stamina <= 0 {
+		c.Stamina = 0
+		c.exhausted = true
+	}
+	return true
+}
+
+// Knockback pushes the entity by impulse, a change of velocity in world
+// space (world-units per second). Friction wears it off again on the ground;
+// in the air it lasts until landing.
+func (c *ControlComponent) Knockback(impulse engo.Point) {
+	c.velocity.Add(impulse)
+}
+
 // ControlFace is satisfied by any component that embeds *ControlComponent.
 type ControlFace interface {
 	GetControlComponent() *ControlComponent
@@ -86,6 +232,7 @@ type ControlAble interface {
 	ControlFace
 	common.SpaceFace
 	ArcheryAble
+	InputFace
 }
 
 type controlEntity struct {
@@ -93,6 +240,7 @@ type controlEntity struct {
 	*ControlComponent
 	*common.SpaceComponent
 	*ArcheryComponent
+	*InputComponent
 }
 
 // hudBar is a minimal HUD entity used internally by ControlSystem for the
@@ -103,9 +251,17 @@ type hudBar struct {
 	common.SpaceComponent
 }
 
-// ControlSystem handles keyboard-driven movement, rotation, sprinting,
-// crouching, jumping, and renders a health and stamina bar as a HUD overlay.
+// ControlSystem turns each entity's InputCommand into movement, rotation,
+// sprinting, crouching, jumping and shooting, and renders a health and
+// stamina bar as a HUD overlay, with a mark pointing the way the entity was
+// last hurt from and a strip for its armor. Entities hurt by directional
+// Damage are knocked back by it. Entities walk up steps and off ledges in
+// Floors, are blocked by rises too high to step up, and are hurt by long
+// falls.
 type ControlSystem struct {
+	// Floors gives the height of the ground. When nil the level is flat.
+	Floors *FloorSystem
+
 	entities []controlEntity
 	w        *ecs.World
 
@@ -113,6 +269,8 @@ type ControlSystem struct {
 	healthBarFg  hudBar // coloured foreground, width scales with health
 	staminaBarBg hudBar // dark background, always full width
 	staminaBarFg hudBar // coloured foreground, width scales with stamina
+	armorBar     hudBar // width scales with armor
+	hurtMark     hudBar // points the way the last hit came from
 }
 
 func (s *ControlSystem) New(w *ecs.World) {
@@ -177,6 +335,60 @@ func (s *ControlSystem) New(w *ecs.World) {
 	}
 	s.staminaBarFg.SetShader(common.LegacyHUDShader)
 	w.AddEntity(&s.staminaBarFg)
+
+	// ── Damage indicator (placed when hit) ────────────────────────────────
+	s.hurtMark = hudBar{BasicEntity: ecs.NewBasic()}
+	s.hurtMark.SpaceComponent = common.SpaceComponent{
+		Width:  hurtMarkW,
+		Height: hurtMarkH,
+	}
+	s.hurtMark.RenderComponent = common.RenderComponent{
+		Drawable:    common.Rectangle{},
+		Color:       color.RGBA{0xFF, 0x22, 0x22, 0xFF},
+		StartZIndex: 11,
+		Hidden:      true,
+	}
+	s.hurtMark.SetShader(common.LegacyHUDShader)
+	w.AddEntity(&s.hurtMark)
+
+	// ── Armor strip (under the health bar) ────────────────────────────────
+	s.armorBar = hudBar{BasicEntity: ecs.NewBasic()}
+	s.armorBar.SpaceComponent = common.SpaceComponent{
+		Position: engo.Point{X: healthBarX, Y: armorBarY},
+		Height:   armorBarH,
+	}
+	s.armorBar.RenderComponent = common.RenderComponent{
+		Drawable:    common.Rectangle{},
+		Color:       color.RGBA{0x33, 0x88, 0xFF, 0xFF},
+		StartZIndex: 11,
+	}
+	s.armorBar.SetShader(common.LegacyHUDShader)
+	w.AddEntity(&s.armorBar)
+
+	engo.Mailbox.Listen(DamageMessageType, s.hurt)
+}
+
+// hurt shows where directional damage to one of the entities came from, and
+// knocks the entity back from there.
+func (s *ControlSystem) hurt(msg engo.Message) {
+	m, ok := msg.(DamageMessage)
+	if !ok || !m.Damage.Directional {
+		return
+	}
+	for _, e := range s.entities {
+		if e.BasicEntity.ID() != m.Target {
+			continue
+		}
+		e.hurtFrom = m.Damage.From
+		e.hurtTime = hurtMarkTime
+		if m.Damage.Knockback > 0 {
+			pos := groundPos(e.Position)
+			away := engo.Point{X: pos.X - m.Damage.From.X, Y: pos.Y - m.Damage.From.Y}
+			away, _ = away.Normalize()
+			away.MultiplyScalar(m.Damage.Knockback)
+			e.Knockback(away)
+		}
+	}
 }
 
 func (s *ControlSystem) Add(
@@ -184,6 +396,7 @@ func (s *ControlSystem) Add(
 	control *ControlComponent,
 	space *common.SpaceComponent,
 	archery *ArcheryComponent,
+	input *InputComponent,
 ) {
 	// Default health and stamina to full when the caller hasn't pre-set them.
 	if control.Health == 0 {
@@ -192,7 +405,13 @@ func (s *ControlSystem) Add(
 	if control.Stamina == 0 {
 		control.Stamina = 100
 	}
-	s.entities = append(s.entities, controlEntity{basic, control, space, archery})
+	if control.Movement == (MovementConfig{}) {
+		control.Movement = DefaultMovement()
+	}
+	if control.StaminaCosts == nil {
+		control.StaminaCosts = DefaultStaminaCosts()
+	}
+	s.entities = append(s.entities, controlEntity{basic, control, space, archery, input})
 }
 
 func (s *ControlSystem) AddByInterface(i ecs.Identifier) {
@@ -200,7 +419,7 @@ func (s *ControlSystem) AddByInterface(i ecs.Identifier) {
 	if !ok {
 		return
 	}
-	s.Add(o.GetBasicEntity(), o.GetControlComponent(), o.GetSpaceComponent(), o.GetArcheryComponent())
+	s.Add(o.GetBasicEntity(), o.GetControlComponent(), o.GetSpaceComponent(), o.GetArcheryComponent(), o.GetInputComponent())
 }
 
 func (s *ControlSystem) Remove(basic ecs.BasicEntity) {
@@ -216,21 +435,34 @@ func (s *ControlSystem) Remove(basic ecs.BasicEntity) {
 	}
 }
 
-func (s *ControlSystem) Update(dt float32) {
+// Update does nothing; entities are moved in Tick.
+func (s *ControlSystem) Update(dt float32) {}
+
+func (s *ControlSystem) Tick(dt float32) {
 	for _, entity := range s.entities {
+		cmd := entity.Command
+
 		// Capture the resting eye-height once (after all system initialisers
 		// have had a chance to set SpaceComponent.Height).
 		if entity.NormalHeight == 0 {
 			entity.NormalHeight = entity.SpaceComponent.Height
 		}
+		entity.Defense.tick(dt)
 
 		// ── Sprint / Stamina ──────────────────────────────────────────────
-		wantSprint := engo.Input.Button("sprint").Down()
+		wantSprint := cmd.Sprint
 		canSprint := !entity.exhausted && entity.Stamina > 0
 		sprinting := wantSprint && canSprint
+		if wantSprint && !canSprint && !entity.wantedSprint {
+			entity.staminaFlash = staminaFlashTime
+		}
+		entity.wantedSprint = wantSprint
+		if entity.staminaFlash > 0 {
+			entity.staminaFlash -= dt
+		}
 
 		if sprinting {
-			entity.Stamina -= staminaDrainRate * dt
+			entity.Stamina -= entity.StaminaCosts["sprint"] * dt
 			if entity.Stamina <= 0 {
 				entity.Stamina = 0
 				entity.exhausted = true
@@ -247,79 +479,154 @@ func (s *ControlSystem) Update(dt float32) {
 		}
 
 		// ── Shooting ──────────────────────────────────────────────────────
-		entity.IsShooting = engo.Input.Mouse.Action == engo.Press && engo.Input.Mouse.Button == engo.MouseButtonLeft
+		entity.IsShooting = cmd.Fire
+		entity.SelectWeapon(cmd.Weapon)
 
 		// --- Reloading -----------------------------------------------------
-		entity.IsReloading = engo.Input.Button("reload").JustPressed()
+		entity.IsReloading = cmd.Reload
 
 		// ── Crouch ───────────────────────────────────────────────────────
-		crouching := engo.Input.Button("crouch").Down()
+		crouching := cmd.Crouch
 
 		// ── Jump ─────────────────────────────────────────────────────────
-		if engo.Input.Button("jump").JustPressed() && !entity.isJumping {
+		if cmd.Jump && !entity.isJumping && entity.spendStamina("jump") {
 			entity.isJumping = true
 			entity.jumpVelocity = jumpInitVel
+			entity.fallFrom = entity.elevation
 		}
 
-		// Base eye-height depends on whether we are crouching.
-		// Crouching *increases* Height, which makes the floor rise on screen
-		// (projection: screen_y = -Height * focal/depth + h/2), lowering the view.
-		baseHeight := entity.NormalHeight
+		// Apply rotation (mouse x-axis) around the entity centre so the
+		// pivot stays fixed under the cursor.
+		ctr := entity.GetSpaceComponent().Center()
+		turn := entity.turnLeft + cmd.Turn*entity.RotSpeed*dt
+		step := math.Clamp(turn, -maxTurnPerTick, maxTurnPerTick)
+		entity.turnLeft = turn - step
+		entity.Rotation += step
+		entity.SetCenter(ctr)
+
+		// ── Horizontal movement ───────────────────────────────────────────
+		move := &entity.Movement
+		topSpeed := entity.Speed
+		if sprinting {
+			topSpeed *= move.SprintMul
+		}
 		if crouching {
-			baseHeight = entity.NormalHeight * (1 + crouchHeightMul)
+			topSpeed *= move.CrouchMul
 		}
 
-		if entity.isJumping {
-			// Decreasing Height raises the view (floor descends on screen).
-			// gravity gradually restores Height back toward baseHeight.
-			entity.jumpVelocity -= gravity * dt
-			entity.SpaceComponent.Height -= entity.jumpVelocity * dt
+		// Keyboard diagonals (and merged sources) can be longer than 1;
+		// anything shorter is an analog stick asking for less speed.
+		dir := cmd.Move
+		if dir.X*dir.X+dir.Y*dir.Y > 1 {
+			dir, _ = dir.Normalize()
+		}
+		// Rotate the commanded direction into world space.
+		sin, cos := math.Sincos(entity.Rotation * math.Pi / 180)
+		wish := engo.Point{
+			X: dir.X*cos - dir.Y*sin,
+			Y: dir.Y*cos + dir.X*sin,
+		}
+		wishSpeed := math.Sqrt(wish.X*wish.X+wish.Y*wish.Y) * topSpeed
+		if wishSpeed > 0 {
+			wish, _ = wish.Normalize()
+		}
 
-			// Land when Height has risen back to (or above) baseHeight.
-			if entity.SpaceComponent.Height >= baseHeight {
-				entity.SpaceComponent.Height = baseHeight
-				entity.isJumping = false
-				entity.jumpVelocity = 0
+		// ── Dash ─────────────────────────────────────────────────────────
+		if entity.dashCooldown > 0 {
+			entity.dashCooldown -= dt
+		}
+		if cmd.Dash && entity.dashCooldown <= 0 && entity.spendStamina("dash") {
+			// Dash the way the entity is moving, or straight ahead when
+			// it is standing still.
+			dashDir := wish
+			if wishSpeed == 0 {
+				dashDir = engo.Point{X: sin, Y: -cos}
 			}
-		} else {
-			// Keep height locked to baseHeight when grounded.
-			entity.SpaceComponent.Height = baseHeight
+			dashDir.MultiplyScalar(entity.Speed * move.DashMul)
+			entity.velocity = dashDir
+			entity.dashTime = move.DashTime
+			entity.dashCooldown = move.DashCooldown
 		}
 
-		// ── Horizontal movement ───────────────────────────────────────────
-		effectiveSpeed := entity.Speed
-		if sprinting {
-			effectiveSpeed *= sprintMultiplier
+		switch {
+		case entity.dashTime > 0:
+			// The dash holds its speed, then hands back no more than the
+			// top speed so that it stays short even in the air.
+			entity.dashTime -= dt
+			if entity.dashTime <= 0 {
+				if speed := math.Sqrt(entity.velocity.X*entity.velocity.X + entity.velocity.Y*entity.velocity.Y); speed > topSpeed {
+					entity.velocity.MultiplyScalar(topSpeed / speed)
+				}
+			}
+		default:
+			accel := move.Accel
+			if entity.isJumping {
+				accel *= move.AirControl
+			} else {
+				applyFriction(&entity.velocity, move.Friction, move.StopSpeed, dt)
+			}
+			accelerate(&entity.velocity, wish, wishSpeed, accel, dt)
 		}
-		if crouching {
-			effectiveSpeed *= crouchSpeedMul
+
+		// ── Ledges ───────────────────────────────────────────────────────
+		delta := engo.Point{X: entity.velocity.X * dt, Y: entity.velocity.Y * dt}
+		if !s.canStep(entity, delta) {
+			// Slide along the ledge when only one axis runs into it.
+			switch {
+			case s.canStep(entity, engo.Point{X: delta.X}):
+				delta.Y, entity.velocity.Y = 0, 0
+			case s.canStep(entity, engo.Point{Y: delta.Y}):
+				delta.X, entity.velocity.X = 0, 0
+			default:
+				delta, entity.velocity = engo.Point{}, engo.Point{}
+			}
 		}
+		entity.Position.Add(delta)
 
-		// Recompute direction every frame from currently-held keys so that
-		// speed changes (sprint/crouch toggle) take effect immediately.
-		dir := s.getDirection()
-		if dir.X != 0 || dir.Y != 0 {
-			dir, _ = dir.Normalize()
+		// ── Vertical movement ─────────────────────────────────────────────
+		entity.Floor = s.Floors.GroundAt(groundPos(entity.Position))
+		switch {
+		case entity.isJumping:
+			entity.jumpVelocity -= gravity * dt
+			entity.elevation += entity.jumpVelocity * dt
+			if entity.elevation > entity.fallFrom {
+				entity.fallFrom = entity.elevation
+			}
+			if entity.elevation <= entity.Floor {
+				land(entity)
+			}
+		case entity.Floor >= entity.elevation-move.StepHeight:
+			// Step up or down, or ride a moving platform.
+			entity.elevation = entity.Floor
+		default:
+			// Walked off a ledge.
+			entity.isJumping = true
+			entity.jumpVelocity = 0
+			entity.fallFrom = entity.elevation
 		}
-		dir.MultiplyScalar(dt * effectiveSpeed)
-		entity.velocity = dir
 
-		// Apply rotation (mouse x-axis) around the entity centre so the
-		// pivot stays fixed under the cursor.
-		ctr := entity.GetSpaceComponent().Center()
-		entity.Rotation += math.Clamp(
-			engo.Input.Axis("hori").Value()*entity.RotSpeed*dt,
-			-5, 5,
-		)
-		entity.SetCenter(ctr)
+		// ── Footsteps ─────────────────────────────────────────────────────
+		// Sprinting on the ground is loud enough for enemies to hear.
+		if sprinting && wishSpeed > 0 && !entity.isJumping {
+			entity.stride -= dt
+			if entity.stride <= 0 {
+				entity.stride = footstepStride
+				engo.Mailbox.Dispatch(NoiseMessage{Pos: groundPos(entity.Position), Radius: footstepNoise})
+			}
+		} else {
+			entity.stride = 0
+		}
 
-		// Rotate the flat movement vector into world space and translate.
-		sin, cos := math.Sincos(entity.Rotation * math.Pi / 180)
-		delta := engo.Point{
-			X: entity.velocity.X*cos - entity.velocity.Y*sin,
-			Y: entity.velocity.Y*cos + entity.velocity.X*sin,
+		// Base eye-height depends on whether we are crouching.
+		// Crouching *increases* Height, which makes the floor rise on screen
+		// (projection: screen_y = -Height * focal/depth + h/2), lowering the view.
+		baseHeight := entity.NormalHeight
+		if crouching {
+			baseHeight = entity.NormalHeight * (1 + crouchHeightMul)
 		}
-		entity.Position.Add(delta)
+		// Decreasing Height raises the view, so standing higher up, on a
+		// raised floor or mid-jump, lowers it by the same amount.
+		entity.SpaceComponent.Height = baseHeight - entity.elevation
 	}
 
 	// ── Health and Stamina HUD update ────────────────────────────────────
@@ -337,6 +644,8 @@ func (s *ControlSystem) Update(dt float32) {
 		healthFrac = 1
 	}
 	s.healthBarFg.Width = healthBarW * healthFrac
+	s.healthBarFg.Hidden = e.Defense.Invulnerable() && int(e.Defense.invulnerable*invulnerableFlashRate)%2 == 0
+	s.armorBar.Width = healthBarW * math.Min(e.Defense.Armor/armorBarMax, 1)
 	switch {
 	case e.Health < 25:
 		s.healthBarFg.Color = color.RGBA{0xFF, 0x22, 0x22, 0xFF} // red: critical
@@ -352,6 +661,13 @@ func (s *ControlSystem) Update(dt float32) {
 	}
 	s.staminaBarFg.Width = staminaBarW * frac
 
+	// Blink the background red while an action is refused for lack of
+	// stamina.
+	s.staminaBarBg.Color = color.RGBA{0x30, 0x30, 0x30, 0xCC}
+	if e.staminaFlash > 0 && int(e.staminaFlash*staminaFlashRate)%2 == 0 {
+		s.staminaBarBg.Color = color.RGBA{0xFF, 0x22, 0x22, 0xCC}
+	}
+
 	switch {
 	case e.exhausted:
 		// Red: sprinting locked out until stamina recovers past 50.
@@ -363,21 +679,91 @@ func (s *ControlSystem) Update(dt float32) {
 		// Green: healthy.
 		s.staminaBarFg.Color = color.RGBA{0x00, 0xFF, 0x44, 0xFF}
 	}
+
+	// Damage indicator, fading out.
+	if e.hurtTime > 0 {
+		e.hurtTime -= dt
+	}
+	s.hurtMark.Hidden = e.hurtTime <= 0
+	if !s.hurtMark.Hidden {
+		s.placeHurtMark(e)
+	}
 }
 
-// getDirection returns the unit movement direction for whichever WASD / arrow
-// keys are currently held.  Diagonal inputs are normalised by the caller.
-func (s *ControlSystem) getDirection() engo.Point {
-	var p engo.Point
-	if engo.Input.Button("up").Down() {
-		p.Y = -1
-	} else if engo.Input.Button("down").Down() {
-		p.Y = 1
-	}
-	if engo.Input.Button("left").Down() {
-		p.X = -1
-	} else if engo.Input.Button("right").Down() {
-		p.X = 1
-	}
-	return p
+// placeHurtMark puts the damage indicator around the middle of the screen on
+// the side e's last hit came from: at the top when it came from straight
+// ahead. The bar lies along that circle, and fades as hurtTime runs out.
+func (s *ControlSystem) placeHurtMark(e controlEntity) {
+	pos := groundPos(e.Position)
+	dx, dy := e.hurtFrom.X-pos.X, e.hurtFrom.Y-pos.Y
+	angle := math.Atan2(dx, -dy)*180/math.Pi - e.Rotation
+	sin, cos := math.Sincos(angle * math.Pi / 180)
+
+	// The bar turns about its top-left corner, so step back from its
+	// middle by half its size, turned.
+	hw, hh := hurtMarkW/2, hurtMarkH/2
+	mid := engo.Point{X: engo.GameWidth()/2 + hurtMarkRadius*sin, Y: engo.GameHeight()/2 - hurtMarkRadius*cos}
+	s.hurtMark.Position = engo.Point{X: mid.X - (hw*cos - hh*sin), Y: mid.Y - (hw*sin + hh*cos)}
+	s.hurtMark.Rotation = angle
+	s.hurtMark.Color = color.RGBA{0xFF, 0x22, 0x22, uint8(0xFF * e.hurtTime / hurtMarkTime)}
+}
+
+// applyFriction slows v by friction, the fraction of its speed lost per
+// second, treating speeds below stopSpeed as stopSpeed.
+func applyFriction(v *engo.Point, friction, stopSpeed, dt float32) {
+	speed := math.Sqrt(v.X*v.X + v.Y*v.Y)
+	if speed == 0 {
+		return
+	}
+	drop := math.Max(speed, stopSpeed) * friction * dt
+	v.MultiplyScalar(math.Max(speed-drop, 0) / speed)
+}
+
+// accelerate speeds v up along the unit vector wish until its speed in that
+// direction reaches wishSpeed, gaining at most accel*wishSpeed per second.
+// Velocity in other directions, such as knockback, is left for friction to
+// wear off, and speed beyond wishSpeed is never taken away here.
+func accelerate(v *engo.Point, wish engo.Point, wishSpeed, accel, dt float32) {
+	current := v.X*wish.X + v.Y*wish.Y
+	add := wishSpeed - current
+	if add <= 0 {
+		return
+	}
+	gain := math.Min(accel*wishSpeed*dt, add)
+	v.X += wish.X * gain
+	v.Y += wish.Y * gain
+}
+
+// canStep reports whether e can move by delta without running into a rise
+// in the floor too high to step up from where its feet are.
+func (s *ControlSystem) canStep(e controlEntity, delta engo.Point) bool {
+	p := e.Position
+	p.Add(delta)
+	return s.Floors.GroundAt(groundPos(p)) <= e.elevation+e.Movement.StepHeight
+}
+
+// groundPos converts an entity's position to wall world-space, where floors
+// are laid out.
+func groundPos(p engo.Point) engo.Point {
+	return engo.Point{X: p.X - shaders.PlayerOffset.X, Y: p.Y - shaders.PlayerOffset.Y}
+}
+
+// eyeHeight returns how high above the base floor the eye of an entity with
+// space is. Its Height is how far below the top of the walls the eye is.
+func eyeHeight(space *common.SpaceComponent) float32 {
+	return defaultWallHeight - space.Height
+}
+
+// land puts an airborne entity down on its Floor, hurting it if it fell
+// further than its Movement allows.
+func land(e controlEntity) {
+	if fall := e.fallFrom - e.Floor; fall > e.Movement.FallSafe {
+		Deal(e.BasicEntity.ID(), &e.Health, &e.Defense, Damage{
+			Amount: (fall - e.Movement.FallSafe) * e.Movement.FallDamage,
+			Type:   DamageFall,
+		})
+	}
+	e.elevation = e.Floor
+	e.isJumping = false
+	e.jumpVelocity = 0
 }
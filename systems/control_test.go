@@ -0,0 +1,128 @@
+package systems
+
+import (
+	"testing"
+
+	"github.com/EngoEngine/ecs"
+	"github.com/EngoEngine/engo"
+	"github.com/EngoEngine/engo/common"
+	"github.com/EngoEngine/engo/math"
+)
+
+// scriptSource is an InputSource that plays back a fixed list of commands,
+// one per tick, and then stands still.
+type scriptSource struct {
+	cmds []InputCommand
+}
+
+func (s *scriptSource) Update(dt float32) {}
+
+func (s *scriptSource) Command() InputCommand {
+	if len(s.cmds) == 0 {
+		return InputCommand{}
+	}
+	c := s.cmds[0]
+	s.cmds = s.cmds[1:]
+	return c
+}
+
+type testPlayer struct {
+	ecs.BasicEntity
+	common.SpaceComponent
+	ControlComponent
+	ArcheryComponent
+	common.AnimationComponent
+	InputComponent
+}
+
+// runScript drives a fresh player with cmds through InputSystem and
+// ControlSystem, one tick each, plus extra idle ticks, and returns it.
+func runScript(cmds []InputCommand, extra int) *testPlayer {
+	p := &testPlayer{BasicEntity: ecs.NewBasic()}
+	p.Speed = 150
+	p.RotSpeed = 25
+	p.SpaceComponent.Height = 20
+	p.Source = &scriptSource{cmds: cmds}
+
+	input := &InputSystem{}
+	input.AddByInterface(p)
+	control := &ControlSystem{}
+	control.AddByInterface(p)
+	for i := 0; i < len(cmds)+extra; i++ {
+		input.Tick(TickDt)
+		control.Tick(TickDt)
+	}
+	return p
+}
+
+func repeat(c InputCommand, n int) []InputCommand {
+	cmds := make([]InputCommand, n)
+	for i := range cmds {
+		cmds[i] = c
+	}
+	return cmds
+}
+
+func TestControlScriptedWalk(t *testing.T) {
+	tests := []struct {
+		name   string
+		move   engo.Point
+		dx, dy int // expected sign of the movement along each axis
+	}{
+		{"forward", engo.Point{Y: -1}, 0, -1},
+		{"back", engo.Point{Y: 1}, 0, 1},
+		{"strafe right", engo.Point{X: 1}, 1, 0},
+		{"strafe left", engo.Point{X: -1}, -1, 0},
+		{"still", engo.Point{}, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := runScript(repeat(InputCommand{Move: tt.move}, 30), 0)
+			if got := sign(p.Position.X); got != tt.dx {
+				t.Errorf("moved to X %v, want sign %d", p.Position.X, tt.dx)
+			}
+			if got := sign(p.Position.Y); got != tt.dy {
+				t.Errorf("moved to Y %v, want sign %d", p.Position.Y, tt.dy)
+			}
+			if p.Rotation != 0 {
+				t.Errorf("turned to %v without turning", p.Rotation)
+			}
+		})
+	}
+}
+
+func TestControlScriptedTurn(t *testing.T) {
+	// One tick's Turn worth of degrees.
+	perTick := func(deg float32) float32 { return deg / (25 * TickDt) }
+	tests := []struct {
+		name  string
+		cmds  []InputCommand
+		ticks int // idle ticks after the script
+		want  float32
+	}{
+		{"small", []InputCommand{{Turn: perTick(3)}}, 0, 3},
+		{"left", []InputCommand{{Turn: perTick(-4)}, {Turn: perTick(-4)}}, 0, -8},
+		// Fast flicks beyond maxTurnPerTick are carried over, not lost.
+		{"flick", []InputCommand{{Turn: perTick(12)}}, 2, 12},
+		{"flick partway", []InputCommand{{Turn: perTick(12)}}, 0, maxTurnPerTick},
+		{"flick back", []InputCommand{{Turn: perTick(20)}, {Turn: perTick(-20)}}, 4, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := runScript(tt.cmds, tt.ticks)
+			if math.Abs(p.Rotation-tt.want) > 1e-3 {
+				t.Errorf("turned to %v, want %v", p.Rotation, tt.want)
+			}
+		})
+	}
+}
+
+func sign(v float32) int {
+	switch {
+	case v > 1e-3:
+		return 1
+	case v < -1e-3:
+		return -1
+	}
+	return 0
+}
@@ -0,0 +1,141 @@
+package systems
+
+import (
+	"github.com/EngoEngine/engo"
+	"github.com/EngoEngine/engo/math"
+)
+
+// armorAbsorb is the fraction of each hit armor soaks up when a Defense
+// leaves ArmorAbsorb unset.
+const armorAbsorb float32 = 0.5
+
+// DamageType is what kind of harm a Damage does. Defenses resist each kind
+// separately.
+type DamageType int
+
+const (
+	DamageFire DamageType = iota
+	DamageProjectile
+	DamageMelee
+	DamageFall
+	DamageCrush
+	DamageExplosion
+)
+
+func (t DamageType) String() string {
+	switch t {
+	case DamageFire:
+		return "fire"
+	case DamageProjectile:
+		return "projectile"
+	case DamageMelee:
+		return "melee"
+	case DamageFall:
+		return "fall"
+	case DamageCrush:
+		return "crush"
+	case DamageExplosion:
+		return "explosion"
+	}
+	return "unknown"
+}
+
+// Damage is one helping of harm on its way to an entity's Health.
+type Damage struct {
+	Amount float32
+	Type   DamageType
+	// Source is the ID of the entity that dealt the damage. Zero is the
+	// level itself, such as lava or a fall.
+	Source uint64
+	// From is where the damage came from, in wall world-space, when
+	// Directional is set.
+	From        engo.Point
+	Directional bool
+	// Knockback is how hard, in world units per second, the damage pushes
+	// its target away from From.
+	Knockback float32
+	// OverTime marks one tick's share of continuous damage, such as standing
+	// in lava. It doesn't start invulnerability frames.
+	OverTime bool
+}
+
+// DamageMessageType is the engo.Mailbox type of DamageMessage.
+const DamageMessageType = "DamageMessage"
+
+// DamageMessage tells everyone that Deal hurt an entity.
+type DamageMessage struct {
+	// Target is the ID of the entity hurt.
+	Target uint64
+	Damage Damage
+	// Dealt is how much Health the target lost, after its Defense.
+	Dealt float32
+	// Killed is set when the damage took the target's last Health.
+	Killed bool
+}
+
+func (DamageMessage) Type() string { return DamageMessageType }
+
+// Defense is how an entity stands up to Damage.
+type Defense struct {
+	// Armor soaks up ArmorAbsorb of each hit, as far as it goes, wearing
+	// away by as much as it soaks up.
+	Armor float32
+	// ArmorAbsorb is the fraction of each hit Armor takes, in [0, 1]. Zero
+	// means armorAbsorb.
+	ArmorAbsorb float32
+	// Resist is the fraction of each type of damage shrugged off: 1 is
+	// immune, and a negative resistance is a weakness. Missing types are
+	// taken in full.
+	Resist map[DamageType]float32
+	// IFrames is how many seconds the entity can't be hurt for after a hit.
+	IFrames float32
+
+	invulnerable float32 // seconds left of IFrames
+}
+
+// Invulnerable reports whether the entity is in its invulnerability frames.
+func (d *Defense) Invulnerable() bool { return d.invulnerable > 0 }
+
+// tick counts down the invulnerability frames.
+func (d *Defense) tick(dt float32) {
+	if d.invulnerable > 0 {
+		d.invulnerable -= dt
+	}
+}
+
+// Deal puts dmg through def and takes whatever gets through off health,
+// never below zero. Resistance goes first, then armor; nothing gets through
+// while def is invulnerable, and a hit that isn't OverTime starts its
+// invulnerability frames. Deal returns how much health was lost, and
+// dispatches a DamageMessage about target when any was.
+func Deal(target uint64, health *float32, def *Defense, dmg Damage) float32 {
+	if *health <= 0 || def.invulnerable > 0 {
+		return 0
+	}
+	amount := dmg.Amount * (1 - def.Resist[dmg.Type])
+	if amount <= 0 {
+		return 0
+	}
+	if def.Armor > 0 {
+		absorb := def.ArmorAbsorb
+		if absorb == 0 {
+			absorb = armorAbsorb
+		}
+		soaked := math.Min(amount*absorb, def.Armor)
+		def.Armor -= soaked
+		amount -= soaked
+	}
+	amount = math.Min(amount, *health)
+	*health -= amount
+	if !dmg.OverTime {
+		def.invulnerable = def.IFrames
+	}
+
+	engo.Mailbox.Dispatch(DamageMessage{
+		Target: target,
+		Damage: dmg,
+		Dealt:  amount,
+		Killed: *health <= 0,
+	})
+	return amount
+}
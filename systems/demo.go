@@ -0,0 +1,358 @@
+package systems
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"math"
+	"os"
+
+	"github.com/EngoEngine/ecs"
+	"github.com/EngoEngine/engo"
+	"github.com/EngoEngine/engo/common"
+)
+
+const (
+	// demoMagic starts every demo file.
+	demoMagic = "SKDM"
+	// demoVersion is bumped whenever the file layout, the meaning of a
+	// recorded command, or the way commands move the player changes.
+	demoVersion byte = 12
+)
+
+// Command flag bits in a recorded command.
+const (
+	demoFire byte = 1 << iota
+	demoCrouch
+	demoSprint
+	demoJump
+	demoReload
+	demoUse
+	demoDash
+	demoMelee
+)
+
+// Command flag bits in the second flags byte of a recorded command.
+const (
+	demoThrow byte = 1 << iota
+)
+
+// Demo is a recorded session: the level and RNG seed it started from and the
+// player's command for every tick, plus a checksum of the player's state
+// after the last one. Playing the commands back from the same level and seed
+// reproduces the session exactly.
+type Demo struct {
+	Level    string
+	Seed     int64
+	Commands []InputCommand
+	Checksum uint32
+}
+
+// LoadDemo reads a demo file written by Save.
+func LoadDemo(path string) (*Demo, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ReadDemo(bufio.NewReader(f))
+}
+
+// Save writes the demo to path.
+func (d *Demo) Save(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(f)
+	if err := d.Write(w); err != nil {
+		f.Close()
+		return err
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Write encodes the demo. All numbers are little-endian:
+//
+//	magic "SKDM", version byte
+//	level: uint16 length, bytes
+//	seed int64, checksum uint32, command count uint32
+//	per command: flags byte, move X and Y as int8 (-127..127), turn float32,
+//	weapon slot byte, second flags byte
+func (d *Demo) Write(w io.Writer) error {
+	if len(d.Level) > math.MaxUint16 {
+		return errors.New("demo: level id too long")
+	}
+	header := []any{
+		[]byte(demoMagic), demoVersion,
+		uint16(len(d.Level)), []byte(d.Level),
+		d.Seed, d.Checksum, uint32(len(d.Commands)),
+	}
+	for _, v := range header {
+		if err := binary.Write(w, binary.LittleEndian, v); err != nil {
+			return err
+		}
+	}
+	for _, c := range d.Commands {
+		b := encodeCommand(c)
+		if _, err := w.Write(b[:]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReadDemo decodes a demo written by Write.
+func ReadDemo(r io.Reader) (*Demo, error) {
+	magic := make([]byte, len(demoMagic))
+	if _, err := io.ReadFull(r, magic); err != nil {
+		return nil, err
+	}
+	if string(magic) != demoMagic {
+		return nil, errors.New("demo: not a demo file")
+	}
+	var version byte
+	if err := binary.Read(r, binary.LittleEndian, &version); err != nil {
+		return nil, err
+	}
+	if version != demoVersion {
+		return nil, fmt.Errorf("demo: unsupported version %d (want %d)", version, demoVersion)
+	}
+
+	var n uint16
+	if err := binary.Read(r, binary.LittleEndian, &n); err != nil {
+		return nil, err
+	}
+	level := make([]byte, n)
+	if _, err := io.ReadFull(r, level); err != nil {
+		return nil, err
+	}
+	d := &Demo{Level: string(level)}
+	var count uint32
+	for _, v := range []any{&d.Seed, &d.Checksum, &count} {
+		if err := binary.Read(r, binary.LittleEndian, v); err != nil {
+			return nil, err
+		}
+	}
+
+	// The rest of the file is the commands. Reading it whole before
+	// allocating keeps a corrupt count from asking for gigabytes.
+	rest, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if want := uint64(count) * demoCommandSize; uint64(len(rest)) < want {
+		return nil, fmt.Errorf("demo: truncated: %d commands need %d bytes, only %d left", count, want, len(rest))
+	}
+	d.Commands = make([]InputCommand, count)
+	var b [demoCommandSize]byte
+	for i := range d.Commands {
+		copy(b[:], rest[i*demoCommandSize:])
+		d.Commands[i] = decodeCommand(b)
+	}
+	return d, nil
+}
+
+// demoCommandSize is the encoded size of one command, in bytes.
+const demoCommandSize = 9
+
+func encodeCommand(c InputCommand) [demoCommandSize]byte {
+	var b [demoCommandSize]byte
+	for _, f := range []struct {
+		on  bool
+		bit byte
+	}{
+		{c.Fire, demoFire}, {c.Crouch, demoCrouch}, {c.Sprint, demoSprint},
+		{c.Jump, demoJump}, {c.Reload, demoReload}, {c.Use, demoUse},
+		{c.Dash, demoDash}, {c.Melee, demoMelee},
+	} {
+		if f.on {
+			b[0] |= f.bit
+		}
+	}
+	b[1] = byte(encodeAxis(c.Move.X))
+	b[2] = byte(encodeAxis(c.Move.Y))
+	binary.LittleEndian.PutUint32(b[3:], math.Float32bits(c.Turn))
+	b[7] = byte(c.Weapon)
+	if c.Throw {
+		b[8] |= demoThrow
+	}
+	return b
+}
+
+func decodeCommand(b [demoCommandSize]byte) InputCommand {
+	return InputCommand{
+		Move: engo.Point{
+			X: float32(int8(b[1])) / 127,
+			Y: float32(int8(b[2])) / 127,
+		},
+		Turn:   math.Float32frombits(binary.LittleEndian.Uint32(b[3:])),
+		Fire:   b[0]&demoFire != 0,
+		Crouch: b[0]&demoCrouch != 0,
+		Sprint: b[0]&demoSprint != 0,
+		Jump:   b[0]&demoJump != 0,
+		Dash:   b[0]&demoDash != 0,
+		Melee:  b[0]&demoMelee != 0,
+		Reload: b[0]&demoReload != 0,
+		Use:    b[0]&demoUse != 0,
+		Throw:  b[8]&demoThrow != 0,
+		Weapon: int(b[7]),
+	}
+}
+
+// encodeAxis maps v in [-1, 1] to [-127, 127].
+func encodeAxis(v float32) int8 {
+	if v > 1 {
+		v = 1
+	}
+	if v < -1 {
+		v = -1
+	}
+	return int8(math.Round(float64(v * 127)))
+}
+
+// RecordingSource passes on the commands of another InputSource, appending
+// each one to Demo. Commands are rounded to what the demo file can hold
+// before they are used, so the recorded session is exactly the one played.
+type RecordingSource struct {
+	Source InputSource
+	Demo   *Demo
+}
+
+func (s *RecordingSource) Update(dt float32) { s.Source.Update(dt) }
+
+func (s *RecordingSource) Command() InputCommand {
+	c := decodeCommand(encodeCommand(s.Source.Command()))
+	s.Demo.Commands = append(s.Demo.Commands, c)
+	return c
+}
+
+// PlaybackSource replays a demo's commands, one per tick. Once they run out
+// it produces empty commands.
+type PlaybackSource struct {
+	Demo *Demo
+	next int
+}
+
+func (s *PlaybackSource) Update(dt float32) {}
+
+func (s *PlaybackSource) Command() InputCommand {
+	if s.Done() {
+		return InputCommand{}
+	}
+	c := s.Demo.Commands[s.next]
+	s.next++
+	return c
+}
+
+// Done reports whether every command has been played.
+func (s *PlaybackSource) Done() bool { return s.next >= len(s.Demo.Commands) }
+
+// DemoEndMessageType is the engo.Mailbox type of DemoEndMessage.
+const DemoEndMessageType = "DemoEndMessage"
+
+// DemoEndMessage is dispatched by DemoSystem once a demo has played to the
+// end.
+type DemoEndMessage struct {
+	// Match reports whether the player ended up exactly as recorded.
+	Match bool
+	// Checksum is the checksum of the player's state after playback.
+	Checksum uint32
+}
+
+func (DemoEndMessage) Type() string { return DemoEndMessageType }
+
+// PlayerChecksum hashes the parts of the player's state that a demo should
+// reproduce.
+func PlayerChecksum(space *common.SpaceComponent, control *ControlComponent) uint32 {
+	h := fnv.New32a()
+	var b [4]byte
+	for _, v := range []float32{
+		space.Position.X, space.Position.Y, space.Rotation, space.Height,
+		control.Health, control.Stamina,
+	} {
+		binary.LittleEndian.PutUint32(b[:], math.Float32bits(v))
+		h.Write(b[:])
+	}
+	return h.Sum32()
+}
+
+// DemoSystem records the player's commands into Demo, or plays Demo back in
+// place of the player's own input, and then compares the outcome against the
+// recorded checksum. It must run last in the tick order, so that it sees the
+// state each tick ends in.
+//
+//	demoSystem := &systems.DemoSystem{Demo: demo, Playback: true}
+//	var demoplayerable *systems.ViewPlayerAble
+//	w.AddSystemInterface(demoSystem, demoplayerable, nil)
+type DemoSystem struct {
+	// Demo is recorded into, or played back.
+	Demo *Demo
+	// Playback plays Demo back instead of recording.
+	Playback bool
+
+	playerID uint64
+	player   *common.SpaceComponent
+	control  *ControlComponent
+	playback *PlaybackSource
+	ended    bool
+}
+
+func (s *DemoSystem) AddByInterface(i ecs.Identifier) {
+	o, ok := i.(ViewPlayerAble)
+	if !ok {
+		return
+	}
+	in, ok := i.(InputFace)
+	if !ok {
+		println("Warning: the player has no InputComponent; demo disabled")
+		return
+	}
+	s.playerID = o.GetBasicEntity().ID()
+	s.player = o.GetSpaceComponent()
+	s.control = o.GetControlComponent()
+
+	input := in.GetInputComponent()
+	if s.Playback {
+		s.playback = &PlaybackSource{Demo: s.Demo}
+		input.Source = s.playback
+		return
+	}
+	if input.Source == nil {
+		input.Source = &KeyboardMouseSource{}
+	}
+	input.Source = &RecordingSource{Source: input.Source, Demo: s.Demo}
+}
+
+func (s *DemoSystem) Remove(basic ecs.BasicEntity) {
+	if s.player != nil && s.playerID == basic.ID() {
+		s.player = nil
+		s.control = nil
+	}
+}
+
+// Update does nothing; the demo follows the ticks.
+func (s *DemoSystem) Update(dt float32) {}
+
+func (s *DemoSystem) Tick(dt float32) {
+	if s.player == nil || s.ended {
+		return
+	}
+	sum := PlayerChecksum(s.player, s.control)
+	if !s.Playback {
+		// Kept up to date so the demo can be saved at any moment.
+		s.Demo.Checksum = sum
+		return
+	}
+	if s.playback.Done() {
+		s.ended = true
+		engo.Mailbox.Dispatch(DemoEndMessage{Match: sum == s.Demo.Checksum, Checksum: sum})
+	}
+}
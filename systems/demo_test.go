@@ -0,0 +1,66 @@
+package systems
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/EngoEngine/engo"
+)
+
+func testDemo() *Demo {
+	return &Demo{
+		Level:    "e1m1",
+		Seed:     -42,
+		Checksum: 0xdeadbeef,
+		Commands: []InputCommand{
+			{},
+			{Move: engo.Point{Y: -1}, Turn: 1.5, Fire: true, Sprint: true},
+			{Move: engo.Point{X: 1, Y: 1}, Turn: -0.25, Crouch: true, Jump: true},
+			{Move: engo.Point{X: -1}, Reload: true, Use: true},
+		},
+	}
+}
+
+func TestDemoRoundTrip(t *testing.T) {
+	d := testDemo()
+	var buf bytes.Buffer
+	if err := d.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	got, err := ReadDemo(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, d) {
+		t.Errorf("read back %+v, want %+v", got, d)
+	}
+}
+
+func TestDemoTruncated(t *testing.T) {
+	var buf bytes.Buffer
+	if err := testDemo().Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	full := buf.Bytes()
+	tests := []struct {
+		name string
+		data []byte
+		want string
+	}{
+		{"empty", nil, "EOF"},
+		{"header", full[:len(demoMagic)+3], "EOF"},
+		{"last command", full[:len(full)-1], "demo: truncated"},
+		{"all commands", full[:len(full)-4*demoCommandSize], "demo: truncated"},
+		{"bad magic", append([]byte("XXXX"), full[len(demoMagic):]...), "not a demo file"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ReadDemo(bytes.NewReader(tt.data))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("got error %v, want one containing %q", err, tt.want)
+			}
+		})
+	}
+}
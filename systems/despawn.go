@@ -0,0 +1,28 @@
+package systems
+
+import (
+	"github.com/EngoEngine/ecs"
+	"github.com/EngoEngine/engo"
+	"github.com/EngoEngine/engo/common"
+)
+
+// despawn removes a helper render entity (billboard, minimap dot, wall quad,
+// ...) that a system created and owns, and releases the GL buffer its shader
+// allocated. Systems call this from Remove so that removing a gameplay entity
+// also cleans up everything that was spawned on its behalf.
+func despawn(w *ecs.World, basic ecs.BasicEntity, ren *common.RenderComponent) {
+	w.RemoveEntity(basic)
+	releaseBuffer(ren)
+}
+
+// releaseBuffer frees the GL buffer the ViewShader or MinimapShader created
+// for ren. Use it directly (instead of despawn) for render entities that share
+// their BasicEntity with the gameplay entity being removed, since the world is
+// already removing that ID from every system.
+func releaseBuffer(ren *common.RenderComponent) {
+	if ren.Buffer != nil && !engo.Headless() {
+		engo.Gl.DeleteBuffer(ren.Buffer)
+	}
+	ren.Buffer = nil
+	ren.BufferContent = nil
+}
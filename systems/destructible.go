@@ -0,0 +1,45 @@
+package systems
+
+import "github.com/EngoEngine/engo/common"
+
+// DestructibleComponent gives a wall hit points. ProjectileSystem subtracts
+// each impacting projectile's Damage from Health, and removes the wall from
+// the world once Health reaches zero, which despawns its 3D quad, minimap
+// rectangle and hitbox in the same frame.
+type DestructibleComponent struct {
+	// Health is the wall's remaining hit-points.
+	Health float32
+	// OnDestroyed is called once, just before the wall is removed. May be nil.
+	OnDestroyed func()
+}
+
+func (c *DestructibleComponent) GetDestructibleComponent() *DestructibleComponent { return c }
+
+// DestructibleFace is satisfied by anything that embeds *DestructibleComponent.
+type DestructibleFace interface {
+	GetDestructibleComponent() *DestructibleComponent
+}
+
+// DestructibleAble is the interface AddByInterface uses to detect
+// destructible walls.
+type DestructibleAble interface {
+	common.BasicFace
+	DestructibleFace
+}
+
+// Damage subtracts amount from Health and reports whether the wall has just
+// been destroyed by this hit.
+func (c *DestructibleComponent) Damage(amount float32) bool {
+	if c.Health <= 0 {
+		return false
+	}
+	c.Health -= amount
+	if c.Health > 0 {
+		return false
+	}
+	c.Health = 0
+	if c.OnDestroyed != nil {
+		c.OnDestroyed()
+	}
+	return true
+}
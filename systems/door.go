@@ -0,0 +1,145 @@
+package systems
+
+import (
+	"github.com/EngoEngine/ecs"
+	"github.com/EngoEngine/engo"
+	"github.com/EngoEngine/engo/common"
+)
+
+// doorSpeed is the default rate a door sinks into the floor or rises back,
+// in world units per second.
+const doorSpeed float32 = 60
+
+// DoorComponent turns a wall into a door that sinks into the floor when the
+// player uses it, and rises back when used again. The wall becomes Passable
+// once it is fully open.
+type DoorComponent struct {
+	// Speed is the door's travel speed in world units per second. It is
+	// initialised to doorSpeed by DoorSystem when zero.
+	Speed float32
+	// Key, when set, locks the door to players not carrying that keycard.
+	Key Key
+
+	open         bool    // target state; the wall animates towards it
+	closedHeight float32 // ViewWallComponent.Height captured when added
+}
+
+func (c *DoorComponent) GetDoorComponent() *DoorComponent { return c }
+
+// SetOpen opens or closes the door. The wall animates towards the new state.
+func (c *DoorComponent) SetOpen(open bool) { c.open = open }
+
+// Opening reports whether the door is open or on its way to being open.
+func (c *DoorComponent) Opening() bool { return c.open }
+
+// DoorFace is satisfied by anything that embeds *DoorComponent.
+type DoorFace interface {
+	GetDoorComponent() *DoorComponent
+}
+
+// DoorAble is the interface AddByInterface uses to detect door entities.
+type DoorAble interface {
+	common.BasicFace
+	DoorFace
+	WallMapFace
+	ViewWallFace
+}
+
+type doorEntity struct {
+	*ecs.BasicEntity
+	*DoorComponent
+	*WallMapComponent
+	*ViewWallComponent
+	tag string
+}
+
+// DoorSystem opens and closes doors in response to UseMessages from
+// UseSystem and to ActionOpen, ActionClose and ActionToggle on tagged doors,
+// animating ViewWallComponent.Height and toggling WallMapComponent.Passable.
+// Scripted actions ignore the door's Key.
+type DoorSystem struct {
+	doors []doorEntity
+}
+
+func (s *DoorSystem) New(w *ecs.World) {
+	engo.Mailbox.Listen(UseMessageType, func(msg engo.Message) {
+		m, ok := msg.(UseMessage)
+		if !ok {
+			return
+		}
+		for _, door := range s.doors {
+			if door.ID() == m.Target.ID() {
+				if unlocks(m.Inventory, door.Key) {
+					door.SetOpen(!door.Opening())
+				}
+				return
+			}
+		}
+	})
+	engo.Mailbox.Listen(ActionMessageType, func(msg engo.Message) {
+		m, ok := msg.(ActionMessage)
+		if !ok {
+			return
+		}
+		for _, door := range s.doors {
+			if door.tag == "" || door.tag != m.Target {
+				continue
+			}
+			switch m.Kind {
+			case ActionOpen:
+				door.SetOpen(true)
+			case ActionClose:
+				door.SetOpen(false)
+			case ActionToggle:
+				door.SetOpen(!door.Opening())
+			}
+		}
+	})
+}
+
+func (s *DoorSystem) AddByInterface(i ecs.Identifier) {
+	o, ok := i.(DoorAble)
+	if !ok {
+		return
+	}
+	door := doorEntity{o.GetBasicEntity(), o.GetDoorComponent(), o.GetWallMapComponent(), o.GetViewWallComponent(), tagOf(i)}
+	if door.Speed == 0 {
+		door.Speed = doorSpeed
+	}
+	door.closedHeight = door.ViewWallComponent.Height
+	if door.closedHeight == 0 {
+		door.closedHeight = defaultWallHeight
+	}
+	s.doors = append(s.doors, door)
+}
+
+func (s *DoorSystem) Remove(basic ecs.BasicEntity) {
+	for i, door := range s.doors {
+		if door.ID() == basic.ID() {
+			s.doors = append(s.doors[:i], s.doors[i+1:]...)
+			return
+		}
+	}
+}
+
+// Update does nothing; doors move in Tick.
+func (s *DoorSystem) Update(dt float32) {}
+
+func (s *DoorSystem) Tick(dt float32) {
+	for _, door := range s.doors {
+		if door.open {
+			door.ViewWallComponent.Height -= door.Speed * dt
+			if door.ViewWallComponent.Height <= 0 {
+				door.ViewWallComponent.Height = 0
+				door.Passable = true
+			}
+			continue
+		}
+		// Solid again as soon as it starts to close.
+		door.Passable = false
+		door.ViewWallComponent.Height += door.Speed * dt
+		if door.ViewWallComponent.Height > door.closedHeight {
+			door.ViewWallComponent.Height = door.closedHeight
+		}
+	}
+}
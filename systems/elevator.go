@@ -0,0 +1,271 @@
+package systems
+
+import (
+	"image/color"
+
+	"github.com/EngoEngine/ecs"
+	"github.com/EngoEngine/engo"
+	"github.com/EngoEngine/engo/common"
+	"github.com/EngoEngine/gl"
+
+	"github.com/SkeleboyStudios/SkeleDoom/shaders"
+)
+
+const (
+	// elevatorSpeed is the default rate a platform's floor moves, in world
+	// units per second.
+	elevatorSpeed float32 = 20
+
+	// crushHeadroom is the least room, in world units, the player's eye
+	// needs below a platform's ceiling before the platform crushes them or
+	// is blocked by them.
+	crushHeadroom float32 = 4
+
+	// crushDPS is the damage per second a crushing platform deals.
+	crushDPS float32 = 40
+)
+
+// ElevatorComponent turns a rectangular area into a platform whose floor
+// moves between two heights. Like lava zones, the area's position and size
+// are given in wall world-space in the entity's SpaceComponent.
+//
+// Heights are in world units above the level's base floor. Every wall's top
+// is at the same level, defaultWallHeight above the base floor, so that is
+// also the ceiling unless Ceiling says otherwise.
+type ElevatorComponent struct {
+	// Low and High are the floor heights the platform moves between. It
+	// starts at Low.
+	Low, High float32
+	// Speed is the platform's travel speed in world units per second. It is
+	// initialised to elevatorSpeed by ElevatorSystem when zero.
+	Speed float32
+	// Wait, when set, makes the platform cycle on its own: it rests Wait
+	// seconds at each end before heading for the other. With no Wait it
+	// only moves on ActionRaise and ActionLower.
+	Wait float32
+	// Ceiling is the height of the ceiling over the platform, for platforms
+	// in a shaft that is taller than the surrounding walls. Zero means
+	// defaultWallHeight.
+	Ceiling float32
+	// Crush makes the platform hurt a player it pins against the ceiling.
+	// Otherwise the platform is blocked by them and heads back down.
+	Crush bool
+	// Tex is the texture for the sides of the raised floor.
+	Tex *gl.Texture
+
+	floor float32 // current floor height
+	up    bool    // heading for High rather than Low
+	rest  float32 // time spent resting at the current end
+}
+
+func (c *ElevatorComponent) GetElevatorComponent() *ElevatorComponent { return c }
+
+// Floor returns the platform's current floor height.
+func (c *ElevatorComponent) Floor() float32 { return c.floor }
+
+// ElevatorFace is satisfied by anything that embeds *ElevatorComponent.
+type ElevatorFace interface {
+	GetElevatorComponent() *ElevatorComponent
+}
+
+// ElevatorAble is the interface AddByInterface uses to detect platforms.
+type ElevatorAble interface {
+	common.BasicFace
+	common.SpaceFace
+	ElevatorFace
+}
+
+type elevatorEntity struct {
+	*ecs.BasicEntity
+	*common.SpaceComponent
+	*ElevatorComponent
+	tag     string
+	sides   [4]*floorSide
+	mapRect sprite
+}
+
+// ElevatorSystem moves platforms between their Low and High floor heights,
+// on a timer or on ActionRaise and ActionLower for tagged platforms. The
+// player standing on one rides it through FloorSystem.
+//
+//	var elevatorplayerable *systems.ViewPlayerAble
+//	var elevatorable       *systems.ElevatorAble
+//	w.AddSystemInterface(&systems.ElevatorSystem{}, []any{elevatorplayerable, elevatorable}, nil)
+type ElevatorSystem struct {
+	w *ecs.World
+
+	playerID  uint64
+	player    *common.SpaceComponent
+	control   *ControlComponent
+	elevators []*elevatorEntity
+}
+
+func (s *ElevatorSystem) New(w *ecs.World) {
+	s.w = w
+
+	engo.Mailbox.Listen(ActionMessageType, func(msg engo.Message) {
+		m, ok := msg.(ActionMessage)
+		if !ok || (m.Kind != ActionRaise && m.Kind != ActionLower) {
+			return
+		}
+		for _, e := range s.elevators {
+			if e.tag != "" && e.tag == m.Target {
+				e.up = m.Kind == ActionRaise
+				e.rest = 0
+			}
+		}
+	})
+}
+
+func (s *ElevatorSystem) AddByInterface(i ecs.Identifier) {
+	if o, ok := i.(ViewPlayerAble); ok {
+		s.playerID = o.GetBasicEntity().ID()
+		s.player = o.GetSpaceComponent()
+		s.control = o.GetControlComponent()
+		return
+	}
+	o, ok := i.(ElevatorAble)
+	if !ok {
+		return
+	}
+	e := &elevatorEntity{
+		BasicEntity:       o.GetBasicEntity(),
+		SpaceComponent:    o.GetSpaceComponent(),
+		ElevatorComponent: o.GetElevatorComponent(),
+		tag:               tagOf(i),
+	}
+	if e.Speed == 0 {
+		e.Speed = elevatorSpeed
+	}
+	e.floor = e.Low
+
+	x, y, w, h := e.Position.X, e.Position.Y, e.Width, e.Height
+	corners := [4]engo.Point{{X: x, Y: y}, {X: x + w, Y: y}, {X: x + w, Y: y + h}, {X: x, Y: y + h}}
+	for n := range e.sides {
+		side := &floorSide{BasicEntity: ecs.NewBasic()}
+		side.Wall = engo.Line{P1: corners[n], P2: corners[(n+1)%4]}
+		side.Passable = true
+		side.Tex = e.Tex
+		s.w.AddEntity(side)
+		e.sides[n] = side
+	}
+	// ViewSystem gives walls added with no Height a full one.
+	e.updateSides()
+
+	e.mapRect = sprite{BasicEntity: ecs.NewBasic()}
+	e.mapRect.SpaceComponent = common.SpaceComponent{
+		Position: engo.Point{X: x + MapWallOffsetX, Y: y + MapWallOffsetY},
+		Width:    w,
+		Height:   h,
+	}
+	e.mapRect.RenderComponent = common.RenderComponent{
+		Drawable:    common.Rectangle{},
+		Color:       color.RGBA{0x88, 0x88, 0x88, 0xAA},
+		StartZIndex: 3,
+	}
+	e.mapRect.SetShader(shaders.MinimapShader)
+	s.w.AddEntity(&e.mapRect)
+
+	s.elevators = append(s.elevators, e)
+}
+
+func (s *ElevatorSystem) Remove(basic ecs.BasicEntity) {
+	if s.player != nil && s.playerID == basic.ID() {
+		s.player = nil
+		s.control = nil
+		return
+	}
+	for i, e := range s.elevators {
+		if e.ID() == basic.ID() {
+			for _, side := range e.sides {
+				s.w.RemoveEntity(side.BasicEntity)
+			}
+			despawn(s.w, e.mapRect.BasicEntity, &e.mapRect.RenderComponent)
+			s.elevators = append(s.elevators[:i], s.elevators[i+1:]...)
+			return
+		}
+	}
+}
+
+// Update does nothing; platforms move in Tick.
+func (s *ElevatorSystem) Update(dt float32) {}
+
+func (s *ElevatorSystem) Tick(dt float32) {
+	var pos engo.Point
+	if s.player != nil {
+		po := shaders.PlayerOffset
+		pos = engo.Point{X: s.player.Position.X - po.X, Y: s.player.Position.Y - po.Y}
+	}
+
+	for _, e := range s.elevators {
+		onboard := s.player != nil && e.contains(pos)
+
+		target := e.Low
+		if e.up {
+			target = e.High
+		}
+		switch {
+		case e.floor == target:
+			if e.Wait > 0 {
+				e.rest += dt
+				if e.rest >= e.Wait {
+					e.up = !e.up
+					e.rest = 0
+				}
+			}
+		case e.floor < target:
+			next := e.floor + e.Speed*dt
+			if next > target {
+				next = target
+			}
+			// NormalHeight is only known once ControlSystem has run.
+			if onboard && s.control.NormalHeight != 0 && s.headroom(e, next) < crushHeadroom {
+				if !e.Crush {
+					// Blocked by the player: give up and go back down.
+					e.up = false
+					break
+				}
+				// Pin the player against the ceiling and hurt them.
+				Deal(s.playerID, &s.control.Health, &s.control.Defense, Damage{
+					Amount:   crushDPS * dt,
+					Type:     DamageCrush,
+					OverTime: true,
+				})
+				break
+			}
+			e.floor = next
+		default:
+			e.floor -= e.Speed * dt
+			if e.floor < target {
+				e.floor = target
+			}
+		}
+		e.updateSides()
+	}
+}
+
+// headroom returns the room between the player's eye and e's ceiling were e's
+// floor at the given height.
+func (s *ElevatorSystem) headroom(e *elevatorEntity, floor float32) float32 {
+	ceiling := e.Ceiling
+	if ceiling == 0 {
+		ceiling = defaultWallHeight
+	}
+	// The player's eye sits NormalHeight below the top of the walls.
+	eye := defaultWallHeight - s.control.NormalHeight
+	return ceiling - floor - eye
+}
+
+// updateSides resizes e's side walls to its current floor height.
+func (e *elevatorEntity) updateSides() {
+	for _, side := range e.sides {
+		side.ViewWallComponent.Height = e.floor
+		side.Offset = defaultWallHeight - e.floor
+	}
+}
+
+// contains reports whether p, in wall world-space, is on the platform.
+func (e *elevatorEntity) contains(p engo.Point) bool {
+	return p.X >= e.Position.X && p.X <= e.Position.X+e.Width &&
+		p.Y >= e.Position.Y && p.Y <= e.Position.Y+e.Height
+}
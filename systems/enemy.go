@@ -0,0 +1,710 @@
+package systems
+
+import (
+	"image/color"
+
+	"github.com/EngoEngine/ecs"
+	"github.com/EngoEngine/engo"
+	"github.com/EngoEngine/engo/common"
+	"github.com/EngoEngine/engo/math"
+	"github.com/EngoEngine/gl"
+
+	"github.com/SkeleboyStudios/SkeleDoom/shaders"
+)
+
+const (
+	// Defaults for unset EnemyComponent fields.
+	enemyHealth float32 = 50
+	enemySpeed  float32 = 60 // world units per second
+	enemyReach  float32 = 20 // how close an enemy comes to the player
+	enemyW      float32 = 20 // billboard width
+	enemyH      float32 = 40 // billboard height
+
+	enemyAttackRange  float32 = 250 // how far away enemies shoot from
+	enemyAttackDelay  float32 = 1.5 // seconds between shots
+	enemyAttackDamage float32 = 10  // damage dealt by each shot
+	enemyMeleeDamage  float32 = 15  // damage dealt by each strike in reach
+	enemyMeleePush    float32 = 250 // knockback of each strike
+	enemyMuzzleHeight float32 = 30  // how high above the floor shots are fired from
+
+	// enemyRepathTime is roughly how often an enemy plans its way to the
+	// player again, in seconds. Each enemy waits a random extra up to half
+	// as long again, so that they don't all plan on the same tick.
+	enemyRepathTime float32 = 0.5
+
+	// enemyWaypointReach is how close an enemy must come to a waypoint
+	// before heading for the next one.
+	enemyWaypointReach float32 = 2
+
+	// enemyPushFriction is the fraction of its knockback speed a pushed
+	// enemy loses each second, and enemyPushStop the speed it stops at.
+	enemyPushFriction float32 = 8
+	enemyPushStop     float32 = 10
+
+	// enemySearchTime is how long an alerted enemy looks around where it
+	// heard or last saw the player before giving up, in seconds, and
+	// enemyLookSpeed how fast it turns while it does, in degrees per second.
+	enemySearchTime float32 = 4
+	enemyLookSpeed  float32 = 90
+)
+
+// EnemyComponent makes an entity a hostile that hunts the player down once
+// it has seen or heard them. Its position and facing are the entity's
+// SpaceComponent: Position in wall world-space, and Rotation in degrees (same
+// convention as the player's).
+type EnemyComponent struct {
+	// Health is initialised to enemyHealth by EnemySystem when zero.
+	Health float32
+	// Speed is the walking speed in world units per second. It is
+	// initialised to enemySpeed when zero.
+	Speed float32
+	// Reach is how close the enemy comes to the player before stopping. It
+	// is initialised to enemyReach when zero.
+	Reach float32
+	// Tex is the texture shown on the 3D billboard. Nil renders a solid
+	// colour.
+	Tex *gl.Texture
+	// W and H are the billboard's world-unit dimensions. They are
+	// initialised to enemyW and enemyH when zero.
+	W, H float32
+	// Senses are what the enemy notices the player with. They are
+	// initialised to DefaultSenses when zero.
+	Senses Senses
+	// AttackRange is how close the player must be for the enemy to shoot
+	// at them, AttackDelay the seconds between its attacks, AttackDamage
+	// what each shot does and MeleeDamage what each strike does when the
+	// player is within Reach. They are initialised to enemyAttackRange,
+	// enemyAttackDelay, enemyAttackDamage and enemyMeleeDamage when zero.
+	AttackRange, AttackDelay, AttackDamage, MeleeDamage float32
+	// ShotTex is the texture of the enemy's projectiles. Nil renders a
+	// solid colour.
+	ShotTex *gl.Texture
+	// Defense is what protects Health from Damage.
+	Defense Defense
+
+	state     EnemyState
+	lastKnown engo.Point // where the player was last seen or heard
+	search    float32    // seconds left looking around lastKnown
+	attack    float32    // seconds until the enemy may attack again
+	push      engo.Point // knockback velocity, in world units per second
+
+	path       []engo.Point // waypoints still to walk, in wall world-space
+	repath     float32      // seconds until the path is planned again
+	navVersion int          // NavSystem.Version the path was planned on
+}
+
+func (c *EnemyComponent) GetEnemyComponent() *EnemyComponent { return c }
+
+// State returns how aware the enemy is of the player.
+func (c *EnemyComponent) State() EnemyState { return c.state }
+
+// Knockback pushes the enemy by impulse, in world units per second, on top of
+// its own walking. The push wears off quickly.
+func (c *EnemyComponent) Knockback(impulse engo.Point) {
+	c.push.Add(impulse)
+}
+
+// Path returns the waypoints the enemy is still to walk.
+func (c *EnemyComponent) Path() []engo.Point { return c.path }
+
+// EnemyFace is satisfied by anything that embeds *EnemyComponent.
+type EnemyFace interface {
+	GetEnemyComponent() *EnemyComponent
+}
+
+// EnemyAble is the interface AddByInterface uses to detect enemies. Enemies
+// move during ticks, so they are interpolated like the player.
+type EnemyAble interface {
+	common.BasicFace
+	common.SpaceFace
+	EnemyFace
+	InterpolatedFace
+}
+
+// enemyEntity is the system's internal representation of one enemy. Like an
+// item it owns a 3D billboard and a minimap dot.
+type enemyEntity struct {
+	*ecs.BasicEntity
+	*common.SpaceComponent
+	*EnemyComponent
+	*InterpolatedComponent
+
+	billboard sprite
+	mapDot    sprite
+}
+
+// enemyTarget is an enemy as other systems see it, to hurt it.
+type enemyTarget struct {
+	*ecs.BasicEntity
+	*common.SpaceComponent
+	*EnemyComponent
+}
+
+// spawnedEnemy is an enemy created by EnemySystem itself, on ActionSpawn or
+// when a save is restored.
+type spawnedEnemy struct {
+	*ecs.BasicEntity
+	*common.SpaceComponent
+	*EnemyComponent
+	*InterpolatedComponent
+}
+
+// EnemyKilledMessageType is the engo.Mailbox type of EnemyKilledMessage.
+const EnemyKilledMessageType = "EnemyKilledMessage"
+
+// EnemyKilledMessage is dispatched by EnemySystem when an enemy dies, just
+// before it is removed from the world.
+type EnemyKilledMessage struct {
+	ID       uint64
+	Position engo.Point // in wall world-space
+	// Killer is the Source of the damage that killed it.
+	Killer uint64
+}
+
+func (EnemyKilledMessage) Type() string { return EnemyKilledMessageType }
+
+// EnemySystem runs the enemies. Idle enemies stand their ground until they
+// see the player or hear a NoiseMessage; then they hunt the player down, or
+// go to look where the noise came from, along paths planned by Nav. Enemies
+// that lose sight of the player search where they last saw them, and go back
+// to idling if they don't find them. EnemySystem also spawns enemies at
+// markers on ActionSpawn, already alerted to where the player is. With the
+// "paths" control it draws every enemy's path on the minimap, coloured by
+// how alert the enemy is. Enemies that see the player within AttackRange
+// shoot at them with Projectiles, and strike them within Reach. Enemies that
+// are hurt are knocked back and turn to where it came from, and die when
+// their Health runs out.
+//
+//	enemies := &systems.EnemySystem{Nav: nav, Spawn: systems.EnemyComponent{Tex: tex}}
+//	var enemyplayerable *systems.ViewPlayerAble
+//	var enemyable       *systems.EnemyAble
+//	var enemymarkerable *systems.MarkerAble
+//	w.AddSystemInterface(enemies, []any{enemyplayerable, enemyable, enemymarkerable}, nil)
+type EnemySystem struct {
+	// Nav finds the enemies' way around walls. When nil they head straight
+	// for the player.
+	Nav *NavSystem
+	// Projectiles fires the enemies' shots. When nil they don't shoot.
+	Projectiles *ProjectileSystem
+	// Spawn is the enemy ActionSpawn creates at each marker it names.
+	Spawn EnemyComponent
+	// Controls holds the "paths" binding. When nil only its keys are
+	// checked.
+	Controls *ControlsConfig
+
+	w             *ecs.World
+	playerID      uint64
+	player        *common.SpaceComponent
+	playerControl *ControlComponent
+	markers       []markerEntity
+	enemies       []*enemyEntity
+	noises        []NoiseMessage // heard since the last tick
+
+	showPaths bool
+	overlay   []*sprite // minimap path segments, reused from frame to frame
+}
+
+func (s *EnemySystem) New(w *ecs.World) {
+	s.w = w
+
+	engo.Mailbox.Listen(ActionMessageType, func(msg engo.Message) {
+		m, ok := msg.(ActionMessage)
+		if !ok || m.Kind != ActionSpawn {
+			return
+		}
+		ec := s.alerted(s.Spawn)
+		for _, mk := range s.markers {
+			if mk.Name == m.Target {
+				s.spawn(mk.Position, mk.Rotation, ec)
+			}
+		}
+	})
+	engo.Mailbox.Listen(NoiseMessageType, func(msg engo.Message) {
+		if m, ok := msg.(NoiseMessage); ok {
+			s.noises = append(s.noises, m)
+		}
+	})
+	engo.Mailbox.Listen(DamageMessageType, func(msg engo.Message) {
+		if m, ok := msg.(DamageMessage); ok {
+			s.hurt(m)
+		}
+	})
+}
+
+// hurt removes an enemy killed by m. One merely hurt is knocked back, and
+// sent to look where the damage came from.
+func (s *EnemySystem) hurt(m DamageMessage) {
+	for _, e := range s.enemies {
+		if e.ID() != m.Target {
+			continue
+		}
+		if m.Killed {
+			engo.Mailbox.Dispatch(EnemyKilledMessage{ID: e.ID(), Position: e.Position, Killer: m.Damage.Source})
+			s.w.RemoveEntity(*e.BasicEntity)
+			return
+		}
+		if !m.Damage.Directional {
+			return
+		}
+		if m.Damage.Knockback > 0 {
+			away := engo.Point{X: e.Position.X - m.Damage.From.X, Y: e.Position.Y - m.Damage.From.Y}
+			away, _ = away.Normalize()
+			away.MultiplyScalar(m.Damage.Knockback)
+			e.Knockback(away)
+		}
+		if e.state != EnemyHunting {
+			e.face(m.Damage.From)
+			e.state = EnemyAlert
+			e.lastKnown = m.Damage.From
+			e.search = enemySearchTime
+			e.repath = 0
+		}
+		return
+	}
+}
+
+// alerted returns ec, set to go and look where the player is now.
+func (s *EnemySystem) alerted(ec EnemyComponent) EnemyComponent {
+	if s.player != nil {
+		ec.state = EnemyAlert
+		ec.lastKnown = s.playerPos()
+		ec.search = enemySearchTime
+	}
+	return ec
+}
+
+// playerPos returns the player's position in wall world-space.
+func (s *EnemySystem) playerPos() engo.Point {
+	po := shaders.PlayerOffset
+	return engo.Point{X: s.player.Position.X - po.X, Y: s.player.Position.Y - po.Y}
+}
+
+func (s *EnemySystem) AddByInterface(i ecs.Identifier) {
+	if o, ok := i.(ViewPlayerAble); ok {
+		s.playerID = o.GetBasicEntity().ID()
+		s.player = o.GetSpaceComponent()
+		s.playerControl = o.GetControlComponent()
+		return
+	}
+	if o, ok := i.(MarkerAble); ok {
+		s.markers = append(s.markers, markerEntity{o.GetBasicEntity(), o.GetSpaceComponent(), o.GetMarkerComponent()})
+		return
+	}
+	o, ok := i.(EnemyAble)
+	if !ok {
+		return
+	}
+
+	ec := o.GetEnemyComponent()
+	sp := o.GetSpaceComponent()
+	if ec.Health == 0 {
+		ec.Health = enemyHealth
+	}
+	if ec.Speed == 0 {
+		ec.Speed = enemySpeed
+	}
+	if ec.Reach == 0 {
+		ec.Reach = enemyReach
+	}
+	if ec.W == 0 {
+		ec.W = enemyW
+	}
+	if ec.H == 0 {
+		ec.H = enemyH
+	}
+	if ec.Senses == (Senses{}) {
+		ec.Senses = DefaultSenses()
+	}
+	if ec.AttackRange == 0 {
+		ec.AttackRange = enemyAttackRange
+	}
+	if ec.AttackDelay == 0 {
+		ec.AttackDelay = enemyAttackDelay
+	}
+	if ec.AttackDamage == 0 {
+		ec.AttackDamage = enemyAttackDamage
+	}
+	if ec.MeleeDamage == 0 {
+		ec.MeleeDamage = enemyMeleeDamage
+	}
+	// Spread the enemies' planning over several ticks.
+	ec.repath = Rand.Float32() * enemyRepathTime
+
+	e := &enemyEntity{
+		BasicEntity:           o.GetBasicEntity(),
+		SpaceComponent:        sp,
+		EnemyComponent:        ec,
+		InterpolatedComponent: o.GetInterpolatedComponent(),
+	}
+	// Drawn where it appears rather than blended in from wherever its
+	// component last was.
+	e.snap()
+
+	// ── 3D billboard ─────────────────────────────────────────────────────
+	e.billboard = sprite{BasicEntity: ecs.NewBasic()}
+	e.billboard.SpaceComponent = common.SpaceComponent{
+		Position: sp.Position,
+		Width:    ec.W,
+		Height:   ec.H,
+	}
+	e.billboard.RenderComponent = common.RenderComponent{
+		Drawable: shaders.Billboard{Pos: sp.Position, W: ec.W, H: ec.H, Tex: ec.Tex},
+		Color:    color.RGBA{0xff, 0xff, 0xff, 0xff},
+	}
+	if ec.Tex == nil {
+		// No texture: render as a solid bone-white quad.
+		e.billboard.Color = color.RGBA{0xe6, 0xe1, 0xcd, 0xff}
+	}
+	e.billboard.SetShader(shaders.ViewShader)
+	s.w.AddEntity(&e.billboard)
+
+	// ── Minimap dot ───────────────────────────────────────────────────────
+	const dotSize float32 = 5
+	e.mapDot = sprite{BasicEntity: ecs.NewBasic()}
+	e.mapDot.SpaceComponent = common.SpaceComponent{Width: dotSize, Height: dotSize}
+	e.mapDot.RenderComponent = common.RenderComponent{
+		Drawable:    common.Rectangle{},
+		Color:       color.RGBA{0xff, 0x00, 0xff, 0xff}, // magenta
+		StartZIndex: 4,
+	}
+	e.mapDot.SetShader(shaders.MinimapShader)
+	s.w.AddEntity(&e.mapDot)
+
+	s.enemies = append(s.enemies, e)
+}
+
+// Remove despawns the enemy's billboard and minimap dot along with it.
+func (s *EnemySystem) Remove(basic ecs.BasicEntity) {
+	if s.player != nil && s.playerID == basic.ID() {
+		s.player = nil
+		s.playerControl = nil
+		return
+	}
+	for i, mk := range s.markers {
+		if mk.ID() == basic.ID() {
+			s.markers = append(s.markers[:i], s.markers[i+1:]...)
+			return
+		}
+	}
+	for i, e := range s.enemies {
+		if e.ID() == basic.ID() {
+			despawn(s.w, e.billboard.BasicEntity, &e.billboard.RenderComponent)
+			despawn(s.w, e.mapDot.BasicEntity, &e.mapDot.RenderComponent)
+			s.enemies = append(s.enemies[:i], s.enemies[i+1:]...)
+			return
+		}
+	}
+}
+
+// spawn adds an enemy at pos, in wall-space, facing rot: this system creates
+// its billboard and map dot, and other systems (e.g. SaveSystem) get to track
+// it too. It returns the new enemy's ID.
+func (s *EnemySystem) spawn(pos engo.Point, rot float32, ec EnemyComponent) uint64 {
+	ec.path = nil
+	e := &spawnedEnemy{
+		BasicEntity: new(ecs.BasicEntity),
+		SpaceComponent: &common.SpaceComponent{
+			Position: pos,
+			Rotation: rot,
+		},
+		EnemyComponent:        &ec,
+		InterpolatedComponent: &InterpolatedComponent{},
+	}
+	*e.BasicEntity = ecs.NewBasic()
+	s.w.AddEntity(e)
+	return e.ID()
+}
+
+// Tick lets each enemy look and listen for the player, and then walks it
+// along its path towards them, or to where it is searching. Paths are planned
+// again every so often, and straight away when the level changes shape or
+// the enemy's goal does.
+func (s *EnemySystem) Tick(dt float32) {
+	if s.player == nil {
+		s.noises = s.noises[:0]
+		return
+	}
+	target := s.playerPos()
+
+	for _, noise := range s.noises {
+		s.hear(noise)
+	}
+	s.noises = s.noises[:0]
+
+	for _, e := range s.enemies {
+		e.Defense.tick(dt)
+		if e.attack > 0 {
+			e.attack -= dt
+		}
+		s.pushed(e, dt)
+
+		// ── Perception ───────────────────────────────────────────────────
+		var walls Occluder
+		if s.Nav != nil {
+			walls = s.Nav
+		}
+		switch {
+		case e.Senses.CanSee(e.Position, e.Rotation, target, walls):
+			if e.state != EnemyHunting {
+				// Give the player a moment before the first shot.
+				e.repath = 0
+				e.attack = e.AttackDelay / 2
+			}
+			e.state = EnemyHunting
+			e.lastKnown = target
+		case e.state == EnemyHunting:
+			// Lost sight of the player: go and look where they were.
+			e.state = EnemyAlert
+			e.search = enemySearchTime
+			e.repath = 0
+		}
+
+		var goal engo.Point
+		switch e.state {
+		case EnemyIdle:
+			continue
+		case EnemyHunting:
+			dx, dy := target.X-e.Position.X, target.Y-e.Position.Y
+			if math.Sqrt(dx*dx+dy*dy) <= e.Reach {
+				e.face(target)
+				s.strike(e)
+				continue
+			}
+			s.shoot(e, target)
+			goal = target
+		case EnemyAlert:
+			goal = e.lastKnown
+		}
+
+		// ── Movement ─────────────────────────────────────────────────────
+		e.repath -= dt
+		if e.repath <= 0 || (s.Nav != nil && e.navVersion != s.Nav.Version()) {
+			s.plan(e, goal)
+		}
+		if len(e.path) == 0 {
+			if e.state == EnemyAlert {
+				// There, or stuck: look around for a while.
+				e.Rotation += enemyLookSpeed * dt
+				e.search -= dt
+				if e.search <= 0 {
+					e.state = EnemyIdle
+				}
+			}
+			continue
+		}
+
+		step := e.Speed * dt
+		for step > 0 && len(e.path) > 0 {
+			next := e.path[0]
+			wx, wy := next.X-e.Position.X, next.Y-e.Position.Y
+			d := math.Sqrt(wx*wx + wy*wy)
+			if d <= enemyWaypointReach {
+				e.path = e.path[1:]
+				continue
+			}
+			move := math.Min(step, d)
+			to := engo.Point{X: e.Position.X + wx/d*move, Y: e.Position.Y + wy/d*move}
+			if s.Nav != nil && s.Nav.Crosses(engo.Line{P1: e.Position, P2: to}) {
+				// Something moved into the way; think again next tick.
+				e.path = nil
+				e.repath = 0
+				break
+			}
+			e.face(next)
+			e.Position = to
+			step -= move
+		}
+	}
+}
+
+// strike hits the player, within e's Reach, once e's attack is ready.
+func (s *EnemySystem) strike(e *enemyEntity) {
+	if e.attack > 0 || s.playerControl == nil {
+		return
+	}
+	Deal(s.playerID, &s.playerControl.Health, &s.playerControl.Defense, Damage{
+		Amount:      e.MeleeDamage,
+		Type:        DamageMelee,
+		Source:      e.ID(),
+		From:        e.Position,
+		Directional: true,
+		Knockback:   enemyMeleePush,
+	})
+	e.attack = e.AttackDelay
+}
+
+// pushed moves e by its knockback, unless a wall is in the way, and wears the
+// knockback down.
+func (s *EnemySystem) pushed(e *enemyEntity, dt float32) {
+	if e.push == (engo.Point{}) {
+		return
+	}
+	to := engo.Point{X: e.Position.X + e.push.X*dt, Y: e.Position.Y + e.push.Y*dt}
+	if s.Nav == nil || !s.Nav.Crosses(engo.Line{P1: e.Position, P2: to}) {
+		e.Position = to
+		e.repath = 0
+	}
+	applyFriction(&e.push, enemyPushFriction, enemyPushStop, dt)
+}
+
+// shoot fires at target, in sight of e, once e's attack is ready and target
+// is within its AttackRange.
+func (s *EnemySystem) shoot(e *enemyEntity, target engo.Point) {
+	dx, dy := target.X-e.Position.X, target.Y-e.Position.Y
+	if e.attack > 0 || s.Projectiles == nil || math.Sqrt(dx*dx+dy*dy) > e.AttackRange {
+		return
+	}
+	s.Projectiles.Fire(e.Position, enemyMuzzleHeight, engo.Point{X: dx, Y: dy}, 0, ProjectileComponent{
+		Tex:     e.ShotTex,
+		Damage:  e.AttackDamage,
+		Owner:   e.BasicEntity.ID(),
+		Faction: FactionEnemy,
+	})
+	e.attack = e.AttackDelay
+}
+
+// hear alerts the enemies that can hear noise, unless they are already
+// hunting the player, and sends them to where it came from.
+func (s *EnemySystem) hear(noise NoiseMessage) {
+	var hearing float32
+	for _, e := range s.enemies {
+		hearing = math.Max(hearing, e.Senses.Hearing)
+	}
+	var grid *NavGrid
+	if s.Nav != nil {
+		grid = s.Nav.Grid
+	}
+	field := Propagate(grid, noise, noise.Radius*hearing)
+	for _, e := range s.enemies {
+		if e.state == EnemyHunting || !field.Heard(e.Position, e.Senses.Hearing) {
+			continue
+		}
+		e.state = EnemyAlert
+		e.lastKnown = noise.Pos
+		e.search = enemySearchTime
+		e.repath = 0
+	}
+}
+
+// plan finds e a new path to target.
+func (s *EnemySystem) plan(e *enemyEntity, target engo.Point) {
+	e.repath = enemyRepathTime * (1 + Rand.Float32()/2)
+	if s.Nav == nil {
+		e.path = []engo.Point{target}
+		return
+	}
+	e.navVersion = s.Nav.Version()
+	path, ok := s.Nav.FindPath(e.Position, target)
+	if !ok {
+		e.path = nil
+		return
+	}
+	e.path = path
+}
+
+// face turns the enemy towards p.
+func (e *enemyEntity) face(p engo.Point) {
+	dx, dy := p.X-e.Position.X, p.Y-e.Position.Y
+	if dx == 0 && dy == 0 {
+		return
+	}
+	// Facing (sin, -cos) of Rotation, as for the player.
+	e.Rotation = math.Atan2(dx, -dy) * 180 / math.Pi
+}
+
+// Update places each enemy's billboard and map dot where it is drawn this
+// frame, and draws the path overlay when it is on.
+func (s *EnemySystem) Update(dt float32) {
+	if s.Controls.JustPressed("paths") {
+		s.showPaths = !s.showPaths
+	}
+	if s.player == nil {
+		return
+	}
+
+	const near float32 = 1.0
+	po := shaders.PlayerOffset
+	playerX := s.player.Position.X - po.X
+	playerY := s.player.Position.Y - po.Y
+	sin, cos := math.Sincos(s.player.Rotation * math.Pi / 180)
+
+	for _, e := range s.enemies {
+		// TickSystem has already blended the enemy's pose between its last
+		// two ticks for this frame.
+		pos := e.Position
+		e.billboard.Position = pos
+		e.billboard.Drawable = shaders.Billboard{Pos: pos, W: e.W, H: e.H, Tex: e.Tex}
+		e.mapDot.Position = engo.Point{
+			X: pos.X + MapWallOffsetX - e.mapDot.Width/2,
+			Y: pos.Y + MapWallOffsetY - e.mapDot.Height/2,
+		}
+
+		// ── Depth z-sorting for the billboard ────────────────────────────
+		relX := pos.X - playerX
+		relY := -pos.Y + playerY
+		camY := relY*cos + relX*sin // camera-space depth
+		if camY < near {
+			e.billboard.Hidden = true
+			continue
+		}
+		e.billboard.Hidden = false
+		// Offset of 50 matches ViewSystem and ItemSystem.
+		e.billboard.SetZIndex(-(camY + 50))
+	}
+
+	s.drawPaths()
+}
+
+// drawPaths lays the minimap path segments over every enemy's remaining
+// path, or hides them all when the overlay is off.
+func (s *EnemySystem) drawPaths() {
+	n := 0
+	if s.showPaths {
+		for _, e := range s.enemies {
+			from := e.Position
+			for _, to := range e.path {
+				s.segment(n, engo.Line{P1: from, P2: to}, pathColors[e.state])
+				from = to
+				n++
+			}
+		}
+	}
+	for _, seg := range s.overlay[n:] {
+		seg.Hidden = true
+	}
+}
+
+// pathColors are the path overlay's colours for each EnemyState.
+var pathColors = map[EnemyState]color.RGBA{
+	EnemyIdle:    {0xaa, 0xaa, 0xaa, 0xcc},
+	EnemyAlert:   {0xff, 0xff, 0x00, 0xcc},
+	EnemyHunting: {0xff, 0x33, 0x00, 0xcc},
+}
+
+// segment shows overlay segment n along l, in wall world-space, in colour c,
+// creating it if needed. It is laid out like MapSystem's walls.
+func (s *EnemySystem) segment(n int, l engo.Line, c color.RGBA) {
+	if n == len(s.overlay) {
+		seg := &sprite{BasicEntity: ecs.NewBasic()}
+		seg.RenderComponent = common.RenderComponent{
+			Drawable:    common.Rectangle{},
+			StartZIndex: 5,
+		}
+		seg.SetShader(shaders.MinimapShader)
+		s.w.AddEntity(seg)
+		s.overlay = append(s.overlay, seg)
+	}
+	seg := s.overlay[n]
+	l.P1.X += MapWallOffsetX
+	l.P2.X += MapWallOffsetX
+	l.P1.Y += MapWallOffsetY
+	l.P2.Y += MapWallOffsetY
+	seg.SpaceComponent = common.SpaceComponent{
+		Position: l.P1,
+		Width:    1,
+		Height:   l.Magnitude(),
+		Rotation: 180 + l.AngleDeg(),
+	}
+	seg.Color = c
+	seg.Hidden = false
+}
@@ -0,0 +1,171 @@
+package systems
+
+import (
+	"image/color"
+
+	"github.com/EngoEngine/ecs"
+	"github.com/EngoEngine/engo"
+	"github.com/EngoEngine/engo/common"
+	"github.com/EngoEngine/gl"
+
+	"github.com/SkeleboyStudios/SkeleDoom/shaders"
+)
+
+// FloorComponent raises the floor of a rectangular area, for steps, ledges
+// and platforms. Like elevators, the area's position and size are given in
+// wall world-space in the entity's SpaceComponent.
+type FloorComponent struct {
+	// Height is the floor's height in world units above the level's base
+	// floor.
+	Height float32
+	// Tex is the texture for the sides of the raised floor.
+	Tex *gl.Texture
+}
+
+func (c *FloorComponent) GetFloorComponent() *FloorComponent { return c }
+
+// FloorFace is satisfied by anything that embeds *FloorComponent.
+type FloorFace interface {
+	GetFloorComponent() *FloorComponent
+}
+
+// FloorAble is the interface AddByInterface uses to detect raised floors.
+type FloorAble interface {
+	common.BasicFace
+	common.SpaceFace
+	FloorFace
+}
+
+// floorSide is one edge of a raised floor, a platform's or an elevator's. It
+// is a regular, always passable wall, so that ViewSystem draws and
+// depth-sorts it with the rest.
+type floorSide struct {
+	ecs.BasicEntity
+
+	common.SpaceComponent
+	WallMapComponent
+	ViewWallComponent
+	NotMapComponent
+}
+
+type floorEntity struct {
+	*ecs.BasicEntity
+	*common.SpaceComponent
+	*FloorComponent
+	sides   [4]*floorSide
+	mapRect sprite
+}
+
+// FloorSystem knows the height of the ground everywhere in the level: the
+// base floor, raised by FloorComponent areas and elevator platforms. It
+// draws the sides of raised floors, and ControlSystem asks it what the
+// player is standing on.
+//
+//	var floorable    *systems.FloorAble
+//	var liftable     *systems.ElevatorAble
+//	floors := &systems.FloorSystem{}
+//	w.AddSystemInterface(floors, []any{floorable, liftable}, nil)
+//	controls := &systems.ControlSystem{Floors: floors}
+type FloorSystem struct {
+	w *ecs.World
+
+	floors    []*floorEntity
+	elevators []*elevatorEntity
+}
+
+func (s *FloorSystem) New(w *ecs.World) { s.w = w }
+
+func (s *FloorSystem) AddByInterface(i ecs.Identifier) {
+	if o, ok := i.(ElevatorAble); ok {
+		// ElevatorSystem draws and moves these; only their floor is needed.
+		s.elevators = append(s.elevators, &elevatorEntity{
+			BasicEntity:       o.GetBasicEntity(),
+			SpaceComponent:    o.GetSpaceComponent(),
+			ElevatorComponent: o.GetElevatorComponent(),
+		})
+		return
+	}
+	o, ok := i.(FloorAble)
+	if !ok {
+		return
+	}
+	f := &floorEntity{
+		BasicEntity:    o.GetBasicEntity(),
+		SpaceComponent: o.GetSpaceComponent(),
+		FloorComponent: o.GetFloorComponent(),
+	}
+
+	x, y, w, h := f.Position.X, f.Position.Y, f.Width, f.SpaceComponent.Height
+	corners := [4]engo.Point{{X: x, Y: y}, {X: x + w, Y: y}, {X: x + w, Y: y + h}, {X: x, Y: y + h}}
+	for n := range f.sides {
+		side := &floorSide{BasicEntity: ecs.NewBasic()}
+		side.Wall = engo.Line{P1: corners[n], P2: corners[(n+1)%4]}
+		side.Passable = true
+		side.Tex = f.Tex
+		s.w.AddEntity(side)
+		// ViewSystem gives walls added with no Height a full one.
+		side.ViewWallComponent.Height = f.FloorComponent.Height
+		side.Offset = defaultWallHeight - f.FloorComponent.Height
+		f.sides[n] = side
+	}
+
+	f.mapRect = sprite{BasicEntity: ecs.NewBasic()}
+	f.mapRect.SpaceComponent = common.SpaceComponent{
+		Position: engo.Point{X: x + MapWallOffsetX, Y: y + MapWallOffsetY},
+		Width:    w,
+		Height:   h,
+	}
+	f.mapRect.RenderComponent = common.RenderComponent{
+		Drawable:    common.Rectangle{},
+		Color:       color.RGBA{0x66, 0x66, 0x66, 0xAA},
+		StartZIndex: 3,
+	}
+	f.mapRect.SetShader(shaders.MinimapShader)
+	s.w.AddEntity(&f.mapRect)
+
+	s.floors = append(s.floors, f)
+}
+
+func (s *FloorSystem) Remove(basic ecs.BasicEntity) {
+	for i, e := range s.elevators {
+		if e.ID() == basic.ID() {
+			s.elevators = append(s.elevators[:i], s.elevators[i+1:]...)
+			return
+		}
+	}
+	for i, f := range s.floors {
+		if f.ID() == basic.ID() {
+			for _, side := range f.sides {
+				s.w.RemoveEntity(side.BasicEntity)
+			}
+			despawn(s.w, f.mapRect.BasicEntity, &f.mapRect.RenderComponent)
+			s.floors = append(s.floors[:i], s.floors[i+1:]...)
+			return
+		}
+	}
+}
+
+// Update does nothing; the ground is only looked up.
+func (s *FloorSystem) Update(dt float32) {}
+
+// GroundAt returns the height of the ground at p, in wall world-space: the
+// highest floor covering it, or the base floor. A nil FloorSystem reports
+// the base floor everywhere.
+func (s *FloorSystem) GroundAt(p engo.Point) float32 {
+	if s == nil {
+		return 0
+	}
+	var ground float32
+	for _, f := range s.floors {
+		if f.FloorComponent.Height > ground && p.X >= f.Position.X && p.X <= f.Position.X+f.Width &&
+			p.Y >= f.Position.Y && p.Y <= f.Position.Y+f.SpaceComponent.Height {
+			ground = f.FloorComponent.Height
+		}
+	}
+	for _, e := range s.elevators {
+		if e.floor > ground && e.contains(p) {
+			ground = e.floor
+		}
+	}
+	return ground
+}
@@ -0,0 +1,272 @@
+package systems
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/EngoEngine/engo"
+	"github.com/EngoEngine/engo/math"
+)
+
+// GamepadButton is a button on a standard (Xbox-layout) gamepad. The
+// triggers count as buttons too, pressed past gamepadTriggerPress.
+type GamepadButton int
+
+const (
+	PadA GamepadButton = iota
+	PadB
+	PadX
+	PadY
+	PadLeftBumper
+	PadRightBumper
+	PadBack
+	PadStart
+	PadGuide
+	PadLeftThumb
+	PadRightThumb
+	PadDpadUp
+	PadDpadRight
+	PadDpadDown
+	PadDpadLeft
+	PadLeftTrigger
+	PadRightTrigger
+	padButtonCount
+)
+
+// gamepadTriggerPress is how far a trigger must be pulled to count as
+// pressed, from 0 to 1.
+const gamepadTriggerPress float32 = 0.5
+
+var padButtonNames = [padButtonCount]string{
+	"A", "B", "X", "Y", "LeftBumper", "RightBumper", "Back", "Start", "Guide",
+	"LeftThumb", "RightThumb", "DpadUp", "DpadRight", "DpadDown", "DpadLeft",
+	"LeftTrigger", "RightTrigger",
+}
+
+func (b GamepadButton) String() string {
+	if b < 0 || b >= padButtonCount {
+		return fmt.Sprintf("Pad%d", int(b))
+	}
+	return padButtonNames[b]
+}
+
+func (b GamepadButton) MarshalText() ([]byte, error) { return []byte(b.String()), nil }
+
+func (b *GamepadButton) UnmarshalText(text []byte) error {
+	for i, name := range padButtonNames {
+		if strings.EqualFold(name, string(text)) {
+			*b = GamepadButton(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown gamepad button %q", string(text))
+}
+
+// GamepadState is a snapshot of a gamepad. Stick axes run from -1 to 1, with
+// Y pointing down (pushing a stick forward gives negative Y); triggers run
+// from 0 to 1.
+type GamepadState struct {
+	LeftX, LeftY, RightX, RightY float32
+	LeftTrigger, RightTrigger    float32
+	Buttons                      [padButtonCount]bool
+}
+
+// pressed reports whether b is held, including the triggers.
+func (s *GamepadState) pressed(b GamepadButton) bool {
+	switch b {
+	case PadLeftTrigger:
+		return s.LeftTrigger >= gamepadTriggerPress
+	case PadRightTrigger:
+		return s.RightTrigger >= gamepadTriggerPress
+	}
+	return b >= 0 && b < padButtonCount && s.Buttons[b]
+}
+
+// GamepadConfig is the gamepad part of ControlsConfig.
+type GamepadConfig struct {
+	// Bindings maps controls to gamepad buttons, like
+	// ControlsConfig.Bindings does to keys.
+	Bindings map[string][]GamepadButton
+	// Deadzone is how far a stick can rest off-centre, from 0 to 1, before
+	// it counts as pushed.
+	Deadzone float32
+	// Curve shapes the sticks' response past the deadzone: 1 is linear, and
+	// higher values give finer control near the centre.
+	Curve float32
+	// LookSpeed scales how fast the right stick turns the view.
+	LookSpeed float32
+	// InvertLook turns the view the opposite way to the right stick.
+	InvertLook bool
+}
+
+// defaultGamepad returns the standard gamepad settings.
+func defaultGamepad() GamepadConfig {
+	return GamepadConfig{
+		Bindings: map[string][]GamepadButton{
+			"fire":    {PadRightTrigger},
+			"jump":    {PadA},
+			"dash":    {PadRightBumper},
+			"melee":   {PadLeftBumper},
+			"throw":   {PadLeftTrigger},
+			"crouch":  {PadB, PadRightThumb},
+			"reload":  {PadX},
+			"use":     {PadY},
+			"sprint":  {PadLeftThumb},
+			"weapon1": {PadDpadUp},
+			"weapon2": {PadDpadRight},
+			"weapon3": {PadDpadDown},
+			"weapon4": {PadDpadLeft},
+		},
+		Deadzone:  0.2,
+		Curve:     2,
+		LookSpeed: 8,
+	}
+}
+
+// shape applies the deadzone and response curve to a stick, keeping its
+// direction. The result's length is from 0 to 1.
+func (c *GamepadConfig) shape(x, y float32) engo.Point {
+	l := math.Sqrt(x*x + y*y)
+	if l <= c.Deadzone {
+		return engo.Point{}
+	}
+	// Rescale so the response starts from zero at the edge of the deadzone.
+	scaled := math.Pow((math.Min(l, 1)-c.Deadzone)/(1-c.Deadzone), c.Curve)
+	return engo.Point{X: x * scaled / l, Y: y * scaled / l}
+}
+
+// GamepadSource is an InputSource for the first connected gamepad: the left
+// stick moves, the right stick turns and the buttons are mapped by the
+// Gamepad part of Controls. Gamepads can be plugged in or out at any time.
+type GamepadSource struct {
+	// Controls holds the gamepad bindings and stick settings. It is
+	// initialised to DefaultControls on the first Update when nil.
+	Controls *ControlsConfig
+
+	state, prev GamepadState
+	connected   bool
+
+	// presses collected since the last Command
+	jump, dash, melee, throw, reload, use bool
+	weapon                                int
+}
+
+// Update reads the gamepad and collects this frame's one-shot presses.
+func (s *GamepadSource) Update(dt float32) {
+	if s.Controls == nil {
+		s.Controls = DefaultControls()
+	}
+	if engo.Headless() {
+		return
+	}
+	s.prev = s.state
+	state, ok := readGamepad()
+	if ok != s.connected {
+		s.connected = ok
+		if ok {
+			engo.Mailbox.Dispatch(HUDMessage{Text: "Gamepad connected"})
+		} else {
+			engo.Mailbox.Dispatch(HUDMessage{Text: "Gamepad disconnected"})
+		}
+	}
+	s.state = state
+	if s.Controls.suspended {
+		s.jump, s.dash, s.melee, s.throw, s.reload, s.use, s.weapon = false, false, false, false, false, false, 0
+		return
+	}
+
+	s.jump = s.jump || s.justPressed("jump")
+	s.dash = s.dash || s.justPressed("dash")
+	s.melee = s.melee || s.justPressed("melee")
+	s.throw = s.throw || s.justPressed("throw")
+	s.reload = s.reload || s.justPressed("reload")
+	s.use = s.use || s.justPressed("use")
+	for slot := 1; slot <= WeaponSlots; slot++ {
+		if s.justPressed(fmt.Sprintf("weapon%d", slot)) {
+			s.weapon = slot
+		}
+	}
+}
+
+func (s *GamepadSource) Command() InputCommand {
+	if !s.connected || s.Controls == nil || s.Controls.suspended {
+		return InputCommand{}
+	}
+	pad := &s.Controls.Gamepad
+	look := pad.shape(s.state.RightX, 0)
+	turn := look.X * pad.LookSpeed
+	if pad.InvertLook {
+		turn = -turn
+	}
+	c := InputCommand{
+		// The stick's Y already points back for a stick pulled back, as
+		// Move's does.
+		Move:   pad.shape(s.state.LeftX, s.state.LeftY),
+		Turn:   turn,
+		Fire:   s.down("fire"),
+		Crouch: s.down("crouch"),
+		Sprint: s.down("sprint"),
+		Jump:   s.jump,
+		Dash:   s.dash,
+		Melee:  s.melee,
+		Throw:  s.throw,
+		Reload: s.reload,
+		Use:    s.use,
+		Weapon: s.weapon,
+	}
+	s.jump, s.dash, s.melee, s.throw, s.reload, s.use, s.weapon = false, false, false, false, false, false, 0
+	return c
+}
+
+func (s *GamepadSource) down(control string) bool {
+	for _, b := range s.Controls.Gamepad.Bindings[control] {
+		if s.state.pressed(b) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *GamepadSource) justPressed(control string) bool {
+	for _, b := range s.Controls.Gamepad.Bindings[control] {
+		if s.state.pressed(b) && !s.prev.pressed(b) {
+			return true
+		}
+	}
+	return false
+}
+
+// MergedSource combines several InputSources, e.g. the keyboard and mouse
+// with a gamepad, so that any of them can drive the entity. Movement and
+// turning add up, and a button is pressed if it is on any source.
+type MergedSource struct {
+	Sources []InputSource
+}
+
+func (s *MergedSource) Update(dt float32) {
+	for _, src := range s.Sources {
+		src.Update(dt)
+	}
+}
+
+func (s *MergedSource) Command() InputCommand {
+	var c InputCommand
+	for _, src := range s.Sources {
+		o := src.Command()
+		c.Move.Add(o.Move)
+		c.Turn += o.Turn
+		c.Fire = c.Fire || o.Fire
+		c.Crouch = c.Crouch || o.Crouch
+		c.Sprint = c.Sprint || o.Sprint
+		c.Jump = c.Jump || o.Jump
+		c.Dash = c.Dash || o.Dash
+		c.Melee = c.Melee || o.Melee
+		c.Throw = c.Throw || o.Throw
+		c.Reload = c.Reload || o.Reload
+		c.Use = c.Use || o.Use
+		if c.Weapon == 0 {
+			c.Weapon = o.Weapon
+		}
+	}
+	return c
+}
@@ -0,0 +1,35 @@
+//go:build (darwin || linux || windows) && !ios && !android && !js && !sdl && !headless && !vulkan
+// +build darwin linux windows
+// +build !ios
+// +build !android
+// +build !js
+// +build !sdl
+// +build !headless
+// +build !vulkan
+
+package systems
+
+import "github.com/go-gl/glfw/v3.3/glfw"
+
+// readGamepad returns the state of the first connected gamepad. It reads
+// GLFW directly rather than through engo.Input, which can't pick up a
+// gamepad plugged in after startup.
+func readGamepad() (GamepadState, bool) {
+	for joy := glfw.Joystick1; joy <= glfw.JoystickLast; joy++ {
+		gs := joy.GetGamepadState()
+		if gs == nil {
+			continue
+		}
+		var s GamepadState
+		for b := PadA; b <= PadDpadLeft; b++ {
+			s.Buttons[b] = gs.Buttons[b] == glfw.Press
+		}
+		s.LeftX, s.LeftY = gs.Axes[glfw.AxisLeftX], gs.Axes[glfw.AxisLeftY]
+		s.RightX, s.RightY = gs.Axes[glfw.AxisRightX], gs.Axes[glfw.AxisRightY]
+		// Triggers rest at -1.
+		s.LeftTrigger = (gs.Axes[glfw.AxisLeftTrigger] + 1) / 2
+		s.RightTrigger = (gs.Axes[glfw.AxisRightTrigger] + 1) / 2
+		return s, true
+	}
+	return GamepadState{}, false
+}
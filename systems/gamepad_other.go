@@ -0,0 +1,7 @@
+//go:build !(darwin || linux || windows) || ios || android || js || sdl || headless || vulkan
+// +build !darwin,!linux,!windows ios android js sdl headless vulkan
+
+package systems
+
+// readGamepad reports no gamepad on backends without GLFW.
+func readGamepad() (GamepadState, bool) { return GamepadState{}, false }
@@ -0,0 +1,110 @@
+package systems
+
+import (
+	"bytes"
+	"image/color"
+
+	"github.com/EngoEngine/ecs"
+	"github.com/EngoEngine/engo"
+	"github.com/EngoEngine/engo/common"
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+const (
+	// hudFontURL is the engo.Files key the embedded Go font is loaded under.
+	hudFontURL = "go.ttf"
+
+	// messageDuration is how long a HUDMessage stays on screen (seconds).
+	messageDuration float32 = 2.5
+	// messageY is the top edge of the message line (screen coordinates).
+	messageY float32 = 20
+)
+
+// newHUDFont returns the embedded Go font at the given size and colour,
+// loading the TTF into engo.Files the first time it is needed. Must be called
+// after the GL context is ready (i.e. from a System's New).
+func newHUDFont(size float64, fg color.Color) *common.Font {
+	if _, err := engo.Files.Resource(hudFontURL); err != nil {
+		if err := engo.Files.LoadReaderData(hudFontURL, bytes.NewReader(goregular.TTF)); err != nil {
+			println("Warning: failed to load HUD font:", err.Error())
+			return nil
+		}
+	}
+	fnt := &common.Font{
+		URL:  hudFontURL,
+		Size: size,
+		FG:   fg,
+	}
+	if err := fnt.CreatePreloaded(); err != nil {
+		println("Warning: failed to create HUD font:", err.Error())
+		return nil
+	}
+	return fnt
+}
+
+// HUDMessageType is the engo.Mailbox type of HUDMessage.
+const HUDMessageType = "HUDMessage"
+
+// HUDMessage asks the MessageSystem to flash a line of text at the top of the
+// screen, e.g. "A secret is revealed!". Dispatch it through engo.Mailbox from
+// any system.
+type HUDMessage struct {
+	Text string
+}
+
+func (HUDMessage) Type() string { return HUDMessageType }
+
+// MessageSystem shows the most recent HUDMessage centred at the top of the
+// screen for messageDuration seconds. It has no entities of its own; add it
+// with w.AddSystem.
+type MessageSystem struct {
+	font      *common.Font
+	text      sprite
+	remaining float32
+}
+
+func (s *MessageSystem) New(w *ecs.World) {
+	s.font = newHUDFont(16, color.White)
+
+	s.text = sprite{BasicEntity: ecs.NewBasic()}
+	s.text.RenderComponent = common.RenderComponent{
+		Drawable:    common.Text{Font: s.font},
+		StartZIndex: 60, // above the lava vignette
+		Hidden:      true,
+	}
+	s.text.SetShader(common.HUDShader)
+	if s.font != nil {
+		w.AddEntity(&s.text)
+	}
+
+	engo.Mailbox.Listen(HUDMessageType, func(msg engo.Message) {
+		m, ok := msg.(HUDMessage)
+		if !ok || s.font == nil {
+			return
+		}
+		s.show(m.Text)
+	})
+}
+
+func (s *MessageSystem) show(text string) {
+	width, _, _ := s.font.TextDimensions(text)
+	s.text.Drawable = common.Text{Font: s.font, Text: text}
+	s.text.Position = engo.Point{
+		X: (engo.GameWidth() - float32(width)) / 2,
+		Y: messageY,
+	}
+	s.text.Hidden = false
+	s.remaining = messageDuration
+}
+
+func (s *MessageSystem) Remove(basic ecs.BasicEntity) {}
+
+func (s *MessageSystem) Update(dt float32) {
+	if s.remaining <= 0 {
+		return
+	}
+	s.remaining -= dt
+	if s.remaining <= 0 {
+		s.text.Hidden = true
+	}
+}
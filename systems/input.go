@@ -0,0 +1,225 @@
+package systems
+
+import (
+	"fmt"
+
+	"github.com/EngoEngine/ecs"
+	"github.com/EngoEngine/engo"
+	"github.com/EngoEngine/engo/common"
+)
+
+// InputCommand is everything an entity wants to do during one tick. Gameplay
+// systems read only this, never engo.Input, so that anything able to fill
+// one in (the keyboard and mouse, a bot, a replay, the network) can drive an
+// entity.
+type InputCommand struct {
+	// Move is the wanted direction relative to the entity's facing: X is
+	// the strafe axis (-1 left, 1 right) and Y the walk axis (-1 forward,
+	// 1 back). Its length, up to 1, scales the speed, so that analog
+	// sticks can move slowly; longer vectors are normalised.
+	Move engo.Point
+	// Turn is the amount to turn by this tick, in the units of the mouse
+	// axis; ControlSystem scales it by ControlComponent.RotSpeed.
+	Turn float32
+
+	// Fire and Crouch and Sprint are held for as long as they are wanted.
+	Fire   bool
+	Crouch bool
+	Sprint bool
+	// Jump, Dash, Melee, Throw, Reload and Use are one-shot: true for a
+	// single tick per press.
+	Jump   bool
+	Dash   bool
+	Melee  bool
+	Throw  bool
+	Reload bool
+	Use    bool
+	// Weapon is the weapon slot (1 to WeaponSlots) picked this tick, or 0.
+	// Like Jump it is one-shot.
+	Weapon int
+}
+
+// InputSource produces InputCommands for an entity.
+type InputSource interface {
+	// Update is called once per rendered frame, for sources that collect
+	// input as it happens.
+	Update(dt float32)
+	// Command returns the command for the next tick. Each one-shot press
+	// and each bit of turning is reported by exactly one command.
+	Command() InputCommand
+}
+
+// KeyboardMouseSource is the default InputSource: the local player's
+// keyboard and mouse, through the bindings in Controls and the "hori" mouse
+// axis registered with engo.Input.
+type KeyboardMouseSource struct {
+	// Controls holds the bindings and mouse settings. It is initialised to
+	// DefaultControls on the first Update when nil.
+	Controls *ControlsConfig
+
+	mouse MouseButtons
+
+	// presses and turning collected since the last Command
+	jump, dash, melee, throw, reload, use bool
+	weapon                                int
+	turn                                  float32
+}
+
+// Update collects this frame's one-shot presses and mouse movement, which
+// would otherwise be lost in frames that run no tick, or counted twice in
+// frames that run several.
+func (s *KeyboardMouseSource) Update(dt float32) {
+	if s.Controls == nil {
+		s.Controls = DefaultControls()
+	}
+	s.mouse.Update()
+	if s.Controls.suspended {
+		// The bindings menu has the keyboard and mouse.
+		s.jump, s.dash, s.melee, s.throw, s.reload, s.use, s.weapon, s.turn = false, false, false, false, false, false, 0, 0
+		return
+	}
+
+	c := s.Controls
+	s.jump = s.jump || c.JustPressed("jump")
+	s.dash = s.dash || c.JustPressed("dash")
+	s.melee = s.melee || c.JustPressed("melee")
+	s.throw = s.throw || c.JustPressed("throw")
+	s.reload = s.reload || c.JustPressed("reload")
+	s.use = s.use || c.JustPressed("use")
+	for slot := 1; slot <= WeaponSlots; slot++ {
+		if c.JustPressed(fmt.Sprintf("weapon%d", slot)) {
+			s.weapon = slot
+		}
+	}
+	turn := engo.Input.Axis("hori").Value() * c.MouseSensitivity
+	if c.InvertMouse {
+		turn = -turn
+	}
+	s.turn += turn
+}
+
+func (s *KeyboardMouseSource) Command() InputCommand {
+	if s.Controls == nil || s.Controls.suspended {
+		return InputCommand{}
+	}
+	c := InputCommand{
+		Move:   s.direction(),
+		Turn:   s.turn,
+		Fire:   s.down("fire"),
+		Crouch: s.down("crouch"),
+		Sprint: s.down("sprint"),
+		Jump:   s.jump,
+		Dash:   s.dash,
+		Melee:  s.melee,
+		Throw:  s.throw,
+		Reload: s.reload,
+		Use:    s.use,
+		Weapon: s.weapon,
+	}
+	s.jump, s.dash, s.melee, s.throw, s.reload, s.use, s.weapon, s.turn = false, false, false, false, false, false, 0, 0
+	return c
+}
+
+func (s *KeyboardMouseSource) down(control string) bool {
+	return s.Controls.Down(control, &s.mouse)
+}
+
+// direction returns the movement direction for whichever movement controls
+// are currently held. Diagonal inputs are normalised by ControlSystem.
+func (s *KeyboardMouseSource) direction() engo.Point {
+	var p engo.Point
+	if s.down("up") {
+		p.Y = -1
+	} else if s.down("down") {
+		p.Y = 1
+	}
+	if s.down("left") {
+		p.X = -1
+	} else if s.down("right") {
+		p.X = 1
+	}
+	return p
+}
+
+// InputComponent holds where an entity's commands come from and the command
+// for the current tick.
+type InputComponent struct {
+	// Source produces the entity's commands. It is initialised to a
+	// KeyboardMouseSource by InputSystem when nil, and may be swapped at
+	// any time (e.g. to hand the player over to a replay).
+	Source InputSource
+	// Command is what the entity wants to do this tick. InputSystem fills
+	// it in at the start of every tick.
+	Command InputCommand
+}
+
+func (c *InputComponent) GetInputComponent() *InputComponent { return c }
+
+// InputFace is satisfied by anything that embeds *InputComponent.
+type InputFace interface {
+	GetInputComponent() *InputComponent
+}
+
+// InputAble is the interface AddByInterface uses to detect input-driven
+// entities.
+type InputAble interface {
+	common.BasicFace
+	InputFace
+}
+
+type inputEntity struct {
+	*ecs.BasicEntity
+	*InputComponent
+}
+
+// inputSystemPriority makes the world update InputSystem even before
+// TickSystem, so that sources have collected this frame's input by the time
+// this frame's ticks ask them for commands.
+const inputSystemPriority = tickSystemPriority + 1
+
+// InputSystem fetches each entity's InputCommand from its InputSource once
+// per tick. It should be the first system in the tick order so that the rest
+// of the tick acts on fresh commands.
+type InputSystem struct {
+	// Controls is given to the KeyboardMouseSources InputSystem creates.
+	Controls *ControlsConfig
+
+	entities []inputEntity
+}
+
+// Priority runs InputSystem before TickSystem.
+func (s *InputSystem) Priority() int { return inputSystemPriority }
+
+func (s *InputSystem) AddByInterface(i ecs.Identifier) {
+	o, ok := i.(InputAble)
+	if !ok {
+		return
+	}
+	c := o.GetInputComponent()
+	if c.Source == nil {
+		c.Source = &KeyboardMouseSource{Controls: s.Controls}
+	}
+	s.entities = append(s.entities, inputEntity{o.GetBasicEntity(), c})
+}
+
+func (s *InputSystem) Remove(basic ecs.BasicEntity) {
+	for i, e := range s.entities {
+		if e.ID() == basic.ID() {
+			s.entities = append(s.entities[:i], s.entities[i+1:]...)
+			return
+		}
+	}
+}
+
+// Update lets every source collect this frame's input.
+func (s *InputSystem) Update(dt float32) {
+	for _, e := range s.entities {
+		e.Source.Update(dt)
+	}
+}
+
+func (s *InputSystem) Tick(dt float32) {
+	for _, e := range s.entities {
+		e.Command = e.Source.Command()
+	}
+}
@@ -0,0 +1,239 @@
+package systems
+
+import (
+	"fmt"
+	"image/color"
+
+	"github.com/EngoEngine/ecs"
+	"github.com/EngoEngine/engo"
+	"github.com/EngoEngine/engo/common"
+)
+
+const (
+	// Held-keys HUD row position and per-key swatch size (screen coordinates).
+	keyRowX   float32 = 10
+	keyRowY   float32 = 170
+	keyW      float32 = 12
+	keyH      float32 = 8
+	keySpacer float32 = 4
+
+	// The bomb counter sits at the right end of the held-keys row: an icon
+	// bombIconSize across, then the count.
+	bombIconX    float32 = 270
+	bombIconY    float32 = 164
+	bombIconSize float32 = 16
+
+	// maxBombs is the most bombs the player can carry.
+	maxBombs = 9
+)
+
+// Key identifies a keycard. Doors and switches with a Key set only open for
+// a player carrying the matching card.
+type Key string
+
+const (
+	KeyRed    Key = "red"
+	KeyBlue   Key = "blue"
+	KeyYellow Key = "yellow"
+)
+
+// KeyColor returns the colour a key is drawn with on the HUD and on its
+// keycard texture. Unknown keys are white.
+func KeyColor(k Key) color.RGBA {
+	switch k {
+	case KeyRed:
+		return color.RGBA{0xEE, 0x22, 0x22, 0xFF}
+	case KeyBlue:
+		return color.RGBA{0x22, 0x55, 0xEE, 0xFF}
+	case KeyYellow:
+		return color.RGBA{0xEE, 0xDD, 0x22, 0xFF}
+	default:
+		return color.RGBA{0xFF, 0xFF, 0xFF, 0xFF}
+	}
+}
+
+// InventoryComponent holds what the player has picked up.
+type InventoryComponent struct {
+	keys  []Key
+	bombs int
+}
+
+func (c *InventoryComponent) GetInventoryComponent() *InventoryComponent { return c }
+
+// HasKey reports whether k is held. A nil inventory holds nothing.
+func (c *InventoryComponent) HasKey(k Key) bool {
+	if c == nil {
+		return false
+	}
+	for _, held := range c.keys {
+		if held == k {
+			return true
+		}
+	}
+	return false
+}
+
+// AddKey puts k into the inventory. Picking up a key twice has no effect.
+func (c *InventoryComponent) AddKey(k Key) {
+	if !c.HasKey(k) {
+		c.keys = append(c.keys, k)
+	}
+}
+
+// Keys returns the held keys in the order they were picked up.
+func (c *InventoryComponent) Keys() []Key { return c.keys }
+
+// Bombs returns how many bombs are held.
+func (c *InventoryComponent) Bombs() int { return c.bombs }
+
+// AddBombs puts n more bombs into the inventory, up to maxBombs.
+func (c *InventoryComponent) AddBombs(n int) {
+	c.bombs = clampInt(c.bombs+n, 0, maxBombs)
+}
+
+// takeBomb uses up one of the held bombs, and reports whether there was one.
+func (c *InventoryComponent) takeBomb() bool {
+	if c.bombs <= 0 {
+		return false
+	}
+	c.bombs--
+	return true
+}
+
+// InventoryFace is satisfied by anything that embeds *InventoryComponent.
+type InventoryFace interface {
+	GetInventoryComponent() *InventoryComponent
+}
+
+// InventoryAble is the interface AddByInterface uses to detect the player.
+type InventoryAble interface {
+	common.BasicFace
+	InventoryFace
+}
+
+// unlocks reports whether inv holds key, and tells the player which key they
+// are missing when it doesn't. An empty key is never locked.
+func unlocks(inv *InventoryComponent, key Key) bool {
+	if key == "" || inv.HasKey(key) {
+		return true
+	}
+	engo.Mailbox.Dispatch(HUDMessage{Text: "You need the " + string(key) + " key"})
+	return false
+}
+
+// InventorySystem draws a row of coloured swatches on the HUD, one per key the
+// player holds, and the number of bombs they have next to BombIcon.
+type InventorySystem struct {
+	// BombIcon is drawn next to the bomb count. Without one the count is
+	// shown on its own.
+	BombIcon *common.Texture
+
+	w         *ecs.World
+	playerID  uint64
+	inventory *InventoryComponent
+	swatches  []*sprite
+
+	font      *common.Font
+	bombIcon  sprite
+	bombCount sprite
+	shown     int // the bomb count on screen; -1 before the first Update
+}
+
+func (s *InventorySystem) New(w *ecs.World) {
+	s.w = w
+	s.shown = -1
+
+	s.bombIcon = sprite{BasicEntity: ecs.NewBasic()}
+	s.bombIcon.Position = engo.Point{X: bombIconX, Y: bombIconY}
+	s.bombIcon.RenderComponent = common.RenderComponent{
+		StartZIndex: 11,
+		Hidden:      true,
+	}
+	if s.BombIcon != nil {
+		s.bombIcon.Drawable = s.BombIcon
+		s.bombIcon.Scale = engo.Point{X: bombIconSize / s.BombIcon.Width(), Y: bombIconSize / s.BombIcon.Height()}
+		s.bombIcon.SetShader(common.HUDShader)
+		w.AddEntity(&s.bombIcon)
+	}
+
+	s.font = newHUDFont(12, color.White)
+	s.bombCount = sprite{BasicEntity: ecs.NewBasic()}
+	s.bombCount.Position = engo.Point{X: bombIconX + bombIconSize + keySpacer, Y: bombIconY}
+	s.bombCount.RenderComponent = common.RenderComponent{
+		Drawable:    common.Text{Font: s.font},
+		StartZIndex: 11,
+		Hidden:      true,
+	}
+	s.bombCount.SetShader(common.HUDShader)
+	if s.font != nil {
+		w.AddEntity(&s.bombCount)
+	}
+}
+
+func (s *InventorySystem) AddByInterface(i ecs.Identifier) {
+	o, ok := i.(InventoryAble)
+	if !ok {
+		return
+	}
+	s.playerID = o.GetBasicEntity().ID()
+	s.inventory = o.GetInventoryComponent()
+}
+
+func (s *InventorySystem) Remove(basic ecs.BasicEntity) {
+	if s.inventory == nil || s.playerID != basic.ID() {
+		return
+	}
+	s.inventory = nil
+	for _, sw := range s.swatches {
+		despawn(s.w, sw.BasicEntity, &sw.RenderComponent)
+	}
+	s.swatches = nil
+	s.bombIcon.Hidden, s.bombCount.Hidden = true, true
+	s.shown = -1
+}
+
+func (s *InventorySystem) Update(dt float32) {
+	if s.inventory == nil {
+		return
+	}
+	// Keys are only ever added during play, so normally just the new ones
+	// need a swatch; start over if the inventory was replaced wholesale.
+	if len(s.swatches) > len(s.inventory.keys) {
+		for _, sw := range s.swatches {
+			despawn(s.w, sw.BasicEntity, &sw.RenderComponent)
+		}
+		s.swatches = nil
+	}
+	for _, k := range s.inventory.keys[len(s.swatches):] {
+		sw := &sprite{BasicEntity: ecs.NewBasic()}
+		sw.SpaceComponent = common.SpaceComponent{
+			Position: engo.Point{
+				X: keyRowX + float32(len(s.swatches))*(keyW+keySpacer),
+				Y: keyRowY,
+			},
+			Width:  keyW,
+			Height: keyH,
+		}
+		sw.RenderComponent = common.RenderComponent{
+			Drawable:    common.Rectangle{},
+			Color:       KeyColor(k),
+			StartZIndex: 11,
+		}
+		sw.SetShader(common.LegacyHUDShader)
+		s.w.AddEntity(sw)
+		s.swatches = append(s.swatches, sw)
+	}
+	s.showBombs()
+}
+
+// showBombs puts the number of bombs held next to the bomb icon, hiding both
+// when there are none.
+func (s *InventorySystem) showBombs() {
+	n := s.inventory.bombs
+	if n == s.shown {
+		return
+	}
+	s.shown = n
+	s.bombIcon.Hidden, s.bombCount.Hidden = n == 0, n == 0
+	s.bombCount.Drawable = common.Text{Font: s.font, Text: fmt.Sprintf("x%d", n)}
+}
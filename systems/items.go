@@ -1,6 +1,7 @@
 package systems
 
 import (
+	"fmt"
 	"image/color"
 
 	"github.com/EngoEngine/ecs"
@@ -26,6 +27,11 @@ type ItemComponent struct {
 	Effect ItemEffect
 	// Radius is the pickup detection distance in world units.
 	Radius float32
+	// Key, when set, is added to the player's inventory on pickup.
+	Key Key
+	// Bombs is how many bombs are added to the player's inventory on
+	// pickup.
+	Bombs int
 }
 
 func (c *ItemComponent) GetItemComponent() *ItemComponent { return c }
@@ -63,8 +69,6 @@ type itemEntity struct {
 		common.RenderComponent
 		common.SpaceComponent
 	}
-
-	pickedUp bool
 }
 
 // ItemSystem manages pickupable items. It must receive both ViewPlayerAble
@@ -76,9 +80,11 @@ type itemEntity struct {
 //	var itemable       *systems.ItemAble
 //	w.AddSystemInterface(&systems.ItemSystem{}, []any{playerviewable, itemable}, nil)
 type ItemSystem struct {
-	w      *ecs.World
-	player *common.SpaceComponent
-	items  []*itemEntity
+	w         *ecs.World
+	playerID  uint64
+	player    *common.SpaceComponent
+	inventory *InventoryComponent // nil when the player has no inventory
+	items     []*itemEntity
 }
 
 func (s *ItemSystem) New(w *ecs.World) {
@@ -88,7 +94,11 @@ func (s *ItemSystem) New(w *ecs.World) {
 func (s *ItemSystem) AddByInterface(i ecs.Identifier) {
 	// Accept the player entity so we have its position/rotation every frame.
 	if o, ok := i.(ViewPlayerAble); ok {
+		s.playerID = o.GetBasicEntity().ID()
 		s.player = o.GetSpaceComponent()
+		if inv, ok := i.(InventoryFace); ok {
+			s.inventory = inv.GetInventoryComponent()
+		}
 		return
 	}
 
@@ -152,48 +162,84 @@ func (s *ItemSystem) AddByInterface(i ecs.Identifier) {
 	s.items = append(s.items, item)
 }
 
+// Remove despawns the item's billboard and minimap dot along with it.
 func (s *ItemSystem) Remove(basic ecs.BasicEntity) {
+	if s.player != nil && s.playerID == basic.ID() {
+		s.player = nil
+		s.inventory = nil
+		return
+	}
 	for i, item := range s.items {
 		if item.BasicEntity.ID() == basic.ID() {
+			despawn(s.w, item.billboard.BasicEntity, &item.billboard.RenderComponent)
+			despawn(s.w, item.mapDot.BasicEntity, &item.mapDot.RenderComponent)
 			s.items = append(s.items[:i], s.items[i+1:]...)
 			return
 		}
 	}
 }
 
-func (s *ItemSystem) Update(dt float32) {
+// Tick picks up items the player is standing close enough to.
+func (s *ItemSystem) Tick(dt float32) {
 	if s.player == nil {
 		return
 	}
 
-	const near float32 = 1.0
-
 	po := shaders.PlayerOffset
 	// Effective player position in wall-space (same coordinate frame as item
 	// positions and wall endpoints).
 	playerX := s.player.Position.X - po.X
 	playerY := s.player.Position.Y - po.Y
 
-	sin, cos := math.Sincos(s.player.Rotation * math.Pi / 180)
+	// Picked-up items are removed from the world once the loop is done so
+	// that s.items isn't spliced while it is being ranged over.
+	var pickedUp []ecs.BasicEntity
 
 	for _, item := range s.items {
-		if item.pickedUp {
-			continue
-		}
-
 		// ── Proximity pickup ─────────────────────────────────────────────
 		dx := playerX - item.SpaceComponent.Position.X
 		dy := playerY - item.SpaceComponent.Position.Y
-		if math.Sqrt(dx*dx+dy*dy) <= item.Radius {
-			item.pickedUp = true
-			item.billboard.Hidden = true
-			item.mapDot.Hidden = true
-			if item.Effect != nil {
-				item.Effect()
-			}
+		if math.Sqrt(dx*dx+dy*dy) > item.Radius {
 			continue
 		}
+		pickedUp = append(pickedUp, *item.BasicEntity)
+		if item.Key != "" && s.inventory != nil {
+			s.inventory.AddKey(item.Key)
+			engo.Mailbox.Dispatch(HUDMessage{Text: "Picked up the " + string(item.Key) + " key"})
+		}
+		if item.Bombs > 0 && s.inventory != nil {
+			s.inventory.AddBombs(item.Bombs)
+			text := fmt.Sprintf("Picked up %d bombs", item.Bombs)
+			if item.Bombs == 1 {
+				text = "Picked up a bomb"
+			}
+			engo.Mailbox.Dispatch(HUDMessage{Text: text})
+		}
+		if item.Effect != nil {
+			item.Effect()
+		}
+	}
 
+	for _, basic := range pickedUp {
+		s.w.RemoveEntity(basic)
+	}
+}
+
+// Update depth-sorts the item billboards against the walls for this frame.
+func (s *ItemSystem) Update(dt float32) {
+	if s.player == nil {
+		return
+	}
+
+	const near float32 = 1.0
+
+	po := shaders.PlayerOffset
+	playerX := s.player.Position.X - po.X
+	playerY := s.player.Position.Y - po.Y
+
+	sin, cos := math.Sincos(s.player.Rotation * math.Pi / 180)
+
+	for _, item := range s.items {
 		// ── Depth z-sorting for the billboard ────────────────────────────
 		// Transform item into camera space to get depth, then set z-index so
 		// items sort correctly relative to walls (which use the same scheme).
@@ -43,7 +43,8 @@ type LavaZoneAble interface {
 // ─── Player interface ─────────────────────────────────────────────────────────
 
 // LavaPlayerAble is satisfied by the player entity, which carries a
-// ControlComponent holding isJumping (grounded check) and Health (damage sink).
+// ControlComponent holding isJumping (grounded check), and Health and Defense
+// (damage sink).
 type LavaPlayerAble interface {
 	common.BasicFace
 	ControlFace
@@ -96,7 +97,7 @@ type LavaSystem struct {
 	w         *ecs.World
 	player    lavaPlayerEntity
 	hasPlayer bool
-	zones     []lavaZoneEntity
+	zones     []*lavaZoneEntity
 
 	// vignette is a full-screen HUD sprite used for the damage flash.
 	vignette      sprite
@@ -158,7 +159,7 @@ func (s *LavaSystem) AddByInterface(i ecs.Identifier) {
 		collision.Main = CollisionGroupLava
 		collision.Group = 0
 
-		zone := lavaZoneEntity{
+		zone := &lavaZoneEntity{
 			BasicEntity:        o.GetBasicEntity(),
 			SpaceComponent:     space,
 			LavaZoneComponent:  z,
@@ -189,16 +190,27 @@ func (s *LavaSystem) AddByInterface(i ecs.Identifier) {
 	}
 }
 
+// Remove forgets the player, or despawns a zone together with its minimap
+// rectangle.
 func (s *LavaSystem) Remove(basic ecs.BasicEntity) {
+	if s.hasPlayer && s.player.ID() == basic.ID() {
+		s.player = lavaPlayerEntity{}
+		s.hasPlayer = false
+		return
+	}
 	for i, z := range s.zones {
 		if z.BasicEntity.ID() == basic.ID() {
+			despawn(s.w, z.mapRect.BasicEntity, &z.mapRect.RenderComponent)
 			s.zones = append(s.zones[:i], s.zones[i+1:]...)
 			return
 		}
 	}
 }
 
-func (s *LavaSystem) Update(dt float32) {
+// Update does nothing; damage and the vignette are updated in Tick.
+func (s *LavaSystem) Update(dt float32) {}
+
+func (s *LavaSystem) Tick(dt float32) {
 	if !s.hasPlayer {
 		return
 	}
@@ -226,10 +238,11 @@ func (s *LavaSystem) Update(dt float32) {
 
 	// Apply accumulated damage.
 	if totalDPS > 0 {
-		s.player.Health -= totalDPS * dt
-		if s.player.Health < 0 {
-			s.player.Health = 0
-		}
+		Deal(s.player.ID(), &s.player.Health, &s.player.Defense, Damage{
+			Amount:   totalDPS * dt,
+			Type:     DamageFire,
+			OverTime: true,
+		})
 	}
 
 	// ── Vignette update ──────────────────────────────────────────────────
@@ -6,6 +6,7 @@ import (
 	"github.com/EngoEngine/ecs"
 	"github.com/EngoEngine/engo"
 	"github.com/EngoEngine/engo/common"
+	"github.com/EngoEngine/engo/math"
 	"github.com/SkeleboyStudios/SkeleDoom/shaders"
 )
 
@@ -64,14 +65,43 @@ type mapPlayerEntity struct {
 	*NotMapComponent
 }
 
+// WallMapComponent holds a wall's geometry in wall world-space. It is read
+// every frame by MapSystem, ViewSystem and ProjectileSystem, so moving,
+// rotating or resizing Wall at runtime updates the minimap rectangle, the 3D
+// quad and the collision shape together.
 type WallMapComponent struct {
 	Wall engo.Line
+	// Passable disables the wall's hitbox and hides it on the minimap, e.g.
+	// once a door has fully opened. Projectiles and "use" pass through it.
+	Passable bool
 }
 
 func (c *WallMapComponent) GetWallMapComponent() *WallMapComponent {
 	return c
 }
 
+// Translate moves both endpoints of the wall by d.
+func (c *WallMapComponent) Translate(d engo.Point) {
+	c.Wall.P1.Add(d)
+	c.Wall.P2.Add(d)
+}
+
+// Rotate turns the wall by deg degrees (clockwise on screen) around its
+// midpoint.
+func (c *WallMapComponent) Rotate(deg float32) {
+	mid := engo.Point{
+		X: (c.Wall.P1.X + c.Wall.P2.X) / 2,
+		Y: (c.Wall.P1.Y + c.Wall.P2.Y) / 2,
+	}
+	sin, cos := math.Sincos(deg * math.Pi / 180)
+	rot := func(p engo.Point) engo.Point {
+		dx, dy := p.X-mid.X, p.Y-mid.Y
+		return engo.Point{X: mid.X + dx*cos - dy*sin, Y: mid.Y + dx*sin + dy*cos}
+	}
+	c.Wall.P1 = rot(c.Wall.P1)
+	c.Wall.P2 = rot(c.Wall.P2)
+}
+
 type WallMapFace interface {
 	GetWallMapComponent() *WallMapComponent
 }
@@ -90,6 +120,14 @@ type mapWallEntity struct {
 	*common.CollisionComponent
 	*WallMapComponent
 	*NotMapComponent
+
+	// source is the gameplay entity's geometry. It is kept out of the
+	// embedded WallMapComponent so the minimap entity itself never looks
+	// like a wall to other systems.
+	source *WallMapComponent
+	// synced is the geometry the minimap rectangle and hitbox were last
+	// built from.
+	synced engo.Line
 }
 
 type sprite struct {
@@ -161,33 +199,9 @@ func (s *MapSystem) AddByInterface(i ecs.Identifier) {
 		s.w.AddEntity(&s.player)
 	}
 	if o, ok := i.(WallMapAble); ok {
-		wa := mapWallEntity{BasicEntity: o.GetBasicEntity()}
-		wall := o.GetWallMapComponent().Wall
-		wall.P1.X += MapWallOffsetX
-		wall.P2.X += MapWallOffsetX
-		wall.P1.Y += MapWallOffsetY
-		wall.P2.Y += MapWallOffsetY
-		wa.SpaceComponent = &common.SpaceComponent{
-			Position: wall.P1,
-			Width:    5,
-			Height:   wall.Magnitude(),
-			Rotation: 180 + wall.AngleDeg(),
-		}
-		lines := []engo.Line{}
-		lines = append(lines, engo.Line{
-			P1: engo.Point{X: 0, Y: 0},
-			P2: engo.Point{X: 5, Y: 0},
-		}, engo.Line{
-			P1: engo.Point{X: 5, Y: 0},
-			P2: engo.Point{X: 5, Y: wall.Magnitude()},
-		}, engo.Line{
-			P1: engo.Point{X: 5, Y: wall.Magnitude()},
-			P2: engo.Point{X: 0, Y: wall.Magnitude()},
-		}, engo.Line{
-			P1: engo.Point{X: 0, Y: wall.Magnitude()},
-			P2: engo.Point{X: 0, Y: 0},
-		})
-		wa.AddShape(common.Shape{Lines: lines})
+		wa := mapWallEntity{BasicEntity: o.GetBasicEntity(), source: o.GetWallMapComponent()}
+		wa.SpaceComponent = &common.SpaceComponent{}
+		s.syncWall(&wa)
 		wa.RenderComponent = &common.RenderComponent{
 			Drawable:    common.Rectangle{},
 			Color:       color.RGBA{0xFF, 0x00, 0x00, 0xFF},
@@ -204,9 +218,82 @@ func (s *MapSystem) AddByInterface(i ecs.Identifier) {
 	}
 }
 
-func (s *MapSystem) Remove(basic ecs.BasicEntity) {}
+// Remove forgets the player or wall with the given ID. The minimap entities
+// share their BasicEntity with the gameplay entity, so the world has already
+// removed them from the RenderSystem and CollisionSystem; only the GL buffer
+// needs releasing here.
+func (s *MapSystem) Remove(basic ecs.BasicEntity) {
+	if s.player.BasicEntity != nil && s.player.ID() == basic.ID() {
+		releaseBuffer(s.player.RenderComponent)
+		s.player = mapPlayerEntity{}
+		return
+	}
+	for i, wall := range s.walls {
+		if wall.BasicEntity.ID() == basic.ID() {
+			releaseBuffer(wall.RenderComponent)
+			s.walls = append(s.walls[:i], s.walls[i+1:]...)
+			return
+		}
+	}
+}
+
+// syncWall rebuilds the minimap rectangle and collision hitbox of wa from its
+// source geometry. The SpaceComponent is replaced in place because engo has no
+// way to clear hitboxes, and the RenderSystem and CollisionSystem hold
+// pointers to it.
+func (s *MapSystem) syncWall(wa *mapWallEntity) {
+	wall := wa.source.Wall
+	wa.synced = wall
+	wall.P1.X += MapWallOffsetX
+	wall.P2.X += MapWallOffsetX
+	wall.P1.Y += MapWallOffsetY
+	wall.P2.Y += MapWallOffsetY
+	*wa.SpaceComponent = common.SpaceComponent{
+		Position: wall.P1,
+		Width:    5,
+		Height:   wall.Magnitude(),
+		Rotation: 180 + wall.AngleDeg(),
+	}
+	lines := []engo.Line{}
+	lines = append(lines, engo.Line{
+		P1: engo.Point{X: 0, Y: 0},
+		P2: engo.Point{X: 5, Y: 0},
+	}, engo.Line{
+		P1: engo.Point{X: 5, Y: 0},
+		P2: engo.Point{X: 5, Y: wall.Magnitude()},
+	}, engo.Line{
+		P1: engo.Point{X: 5, Y: wall.Magnitude()},
+		P2: engo.Point{X: 0, Y: wall.Magnitude()},
+	}, engo.Line{
+		P1: engo.Point{X: 0, Y: wall.Magnitude()},
+		P2: engo.Point{X: 0, Y: 0},
+	})
+	wa.AddShape(common.Shape{Lines: lines})
+}
 
+// Tick picks up walls that were moved, rotated, resized or opened since the
+// last tick, so that collisions use the current geometry.
+func (s *MapSystem) Tick(dt float32) {
+	for i := range s.walls {
+		wa := &s.walls[i]
+		if wa.source.Wall != wa.synced {
+			s.syncWall(wa)
+		}
+		wa.Hidden = wa.source.Passable
+		if wa.source.Passable {
+			wa.CollisionComponent.Group = 0
+		} else {
+			wa.CollisionComponent.Group = CollisionGroupPlaya
+		}
+	}
+}
+
+// Update keeps the minimap camera on the player.
 func (s *MapSystem) Update(dt float32) {
+	if s.player.SpaceComponent == nil {
+		return
+	}
+
 	width, height := s.player.SpaceComponent.Width, s.player.SpaceComponent.Height
 
 	pos := s.player.SpaceComponent.Position
@@ -0,0 +1,180 @@
+package systems
+
+import (
+	"github.com/EngoEngine/ecs"
+	"github.com/EngoEngine/engo"
+	"github.com/EngoEngine/engo/common"
+	"github.com/EngoEngine/engo/math"
+)
+
+const (
+	// Defaults for unset MeleeComponent fields.
+	meleeDamage    float32 = 25
+	meleeRange     float32 = 30  // world units, from the player to the enemy's edge
+	meleeArc       float32 = 100 // degrees
+	meleeKnockback float32 = 300 // world units per second
+	meleeTime      float32 = 0.4 // seconds
+)
+
+// MeleeComponent is the ability to strike whatever is close in front, with
+// the "melee" control. It needs no ammunition.
+type MeleeComponent struct {
+	// Damage is dealt to each enemy struck. It is initialised to
+	// meleeDamage by MeleeSystem when zero.
+	Damage float32
+	// Range is how far the strike reaches. It is initialised to meleeRange
+	// when zero.
+	Range float32
+	// Arc is the full width of the strike in front of the entity, in
+	// degrees. It is initialised to meleeArc when zero.
+	Arc float32
+	// Knockback is how hard enemies struck are pushed away. It is
+	// initialised to meleeKnockback when zero.
+	Knockback float32
+	// Time is how long a swing takes; no other can start until it is
+	// over. It is initialised to meleeTime when zero.
+	Time float32
+
+	swing float32 // seconds left of the current swing
+}
+
+func (c *MeleeComponent) GetMeleeComponent() *MeleeComponent { return c }
+
+// Swing returns how far through its swing the entity is, from 0 as it starts
+// to 1 as it ends, and whether it is swinging at all.
+func (c *MeleeComponent) Swing() (float32, bool) {
+	if c.swing <= 0 || c.Time <= 0 {
+		return 0, false
+	}
+	return 1 - c.swing/c.Time, true
+}
+
+// MeleeFace is satisfied by anything that embeds *MeleeComponent.
+type MeleeFace interface {
+	GetMeleeComponent() *MeleeComponent
+}
+
+// MeleeAble is the interface AddByInterface uses to detect entities that
+// strike in melee.
+type MeleeAble interface {
+	common.BasicFace
+	common.SpaceFace
+	ControlFace
+	InputFace
+	MeleeFace
+}
+
+type meleeEntity struct {
+	*ecs.BasicEntity
+	*common.SpaceComponent
+	*ControlComponent
+	*InputComponent
+	*MeleeComponent
+}
+
+// MeleeSystem swings at enemies on each entity's Melee command, if it has
+// the stamina for it. The strike Deals melee Damage to every enemy within
+// Range and the Arc in front, knocking them back. ViewSystem shows the swing
+// on the player's Hands.
+//
+//	var meleeable      *systems.MeleeAble
+//	var meleeenemyable *systems.EnemyAble
+//	w.AddSystemInterface(&systems.MeleeSystem{}, []any{meleeable, meleeenemyable}, nil)
+type MeleeSystem struct {
+	entities []meleeEntity
+	enemies  []enemyTarget
+}
+
+func (s *MeleeSystem) AddByInterface(i ecs.Identifier) {
+	if o, ok := i.(MeleeAble); ok {
+		m := o.GetMeleeComponent()
+		if m.Damage == 0 {
+			m.Damage = meleeDamage
+		}
+		if m.Range == 0 {
+			m.Range = meleeRange
+		}
+		if m.Arc == 0 {
+			m.Arc = meleeArc
+		}
+		if m.Knockback == 0 {
+			m.Knockback = meleeKnockback
+		}
+		if m.Time == 0 {
+			m.Time = meleeTime
+		}
+		s.entities = append(s.entities, meleeEntity{o.GetBasicEntity(), o.GetSpaceComponent(), o.GetControlComponent(), o.GetInputComponent(), m})
+		return
+	}
+	if o, ok := i.(EnemyAble); ok {
+		s.enemies = append(s.enemies, enemyTarget{o.GetBasicEntity(), o.GetSpaceComponent(), o.GetEnemyComponent()})
+	}
+}
+
+func (s *MeleeSystem) Remove(basic ecs.BasicEntity) {
+	for i, e := range s.entities {
+		if e.ID() == basic.ID() {
+			s.entities = append(s.entities[:i], s.entities[i+1:]...)
+			return
+		}
+	}
+	for i, e := range s.enemies {
+		if e.ID() == basic.ID() {
+			s.enemies = append(s.enemies[:i], s.enemies[i+1:]...)
+			return
+		}
+	}
+}
+
+// Update does nothing; swings are timed in Tick.
+func (s *MeleeSystem) Update(dt float32) {}
+
+func (s *MeleeSystem) Tick(dt float32) {
+	for _, e := range s.entities {
+		if e.swing > 0 {
+			e.swing -= dt
+			continue
+		}
+		if !e.Command.Melee || !e.spendStamina("melee") {
+			continue
+		}
+		e.swing = e.Time
+		s.strike(e)
+	}
+}
+
+// strike deals e's melee damage to every enemy in reach in front of it.
+func (s *MeleeSystem) strike(e meleeEntity) {
+	pos := groundPos(e.Position)
+	sin, cos := math.Sincos(e.Rotation * math.Pi / 180)
+	minCos := math.Cos(e.Arc / 2 * math.Pi / 180)
+
+	// Work out who is hit before dealing any damage, which may remove
+	// enemies from s.enemies.
+	var hit []enemyTarget
+	for _, en := range s.enemies {
+		dx, dy := en.Position.X-pos.X, en.Position.Y-pos.Y
+		d := math.Sqrt(dx*dx + dy*dy)
+		if d > e.Range+en.W/2 {
+			continue
+		}
+		// The facing is (sin, -cos); anything right on top of the entity
+		// is hit whichever way it faces.
+		if d > 0 && (dx*sin-dy*cos)/d < minCos {
+			continue
+		}
+		hit = append(hit, en)
+	}
+	for _, en := range hit {
+		Deal(en.ID(), &en.Health, &en.Defense, Damage{
+			Amount:      e.Damage,
+			Type:        DamageMelee,
+			Source:      e.ID(),
+			From:        pos,
+			Directional: true,
+			Knockback:   e.MeleeComponent.Knockback,
+		})
+	}
+	// The swing is heard nearby.
+	engo.Mailbox.Dispatch(NoiseMessage{Pos: pos, Radius: footstepNoise})
+}
@@ -0,0 +1,418 @@
+package systems
+
+import (
+	"container/heap"
+
+	"github.com/EngoEngine/ecs"
+	"github.com/EngoEngine/engo"
+	"github.com/EngoEngine/engo/math"
+)
+
+const (
+	// navCellSize is the side of a navigation grid cell, in world units.
+	navCellSize float32 = 8
+	// navClearance is how close to a wall the centre of an enemy may come.
+	// Cells whose centre is nearer than this to a wall are blocked.
+	navClearance float32 = 6
+	// navMargin is the open space the grid extends past the outermost walls.
+	navMargin float32 = 64
+)
+
+// navRect is an area of wall world-space, from Min to Max.
+type navRect struct {
+	Min, Max engo.Point
+}
+
+// rectOf returns the bounding box of l, grown by pad on every side.
+func rectOf(l engo.Line, pad float32) navRect {
+	return navRect{
+		Min: engo.Point{X: math.Min(l.P1.X, l.P2.X) - pad, Y: math.Min(l.P1.Y, l.P2.Y) - pad},
+		Max: engo.Point{X: math.Max(l.P1.X, l.P2.X) + pad, Y: math.Max(l.P1.Y, l.P2.Y) + pad},
+	}
+}
+
+// NavGrid is a grid over the level marking which cells an enemy can stand
+// in. It is laid out in wall world-space.
+type NavGrid struct {
+	// Origin is the corner of cell (0, 0).
+	Origin engo.Point
+	// Cell is the side of a cell.
+	Cell float32
+	// W and H are the grid's size in cells.
+	W, H int
+
+	blocked []bool
+}
+
+// newNavGrid returns an open grid covering bounds.
+func newNavGrid(bounds navRect, cell float32) *NavGrid {
+	g := &NavGrid{
+		Origin: bounds.Min,
+		Cell:   cell,
+		W:      int(math.Ceil((bounds.Max.X-bounds.Min.X)/cell)) + 1,
+		H:      int(math.Ceil((bounds.Max.Y-bounds.Min.Y)/cell)) + 1,
+	}
+	g.blocked = make([]bool, g.W*g.H)
+	return g
+}
+
+// cellOf returns the cell containing p, which may be outside the grid.
+func (g *NavGrid) cellOf(p engo.Point) (int, int) {
+	return int(math.Floor((p.X - g.Origin.X) / g.Cell)), int(math.Floor((p.Y - g.Origin.Y) / g.Cell))
+}
+
+// center returns the centre of cell (x, y).
+func (g *NavGrid) center(x, y int) engo.Point {
+	return engo.Point{
+		X: g.Origin.X + (float32(x)+0.5)*g.Cell,
+		Y: g.Origin.Y + (float32(y)+0.5)*g.Cell,
+	}
+}
+
+// Open reports whether cell (x, y) is inside the grid and not blocked.
+func (g *NavGrid) Open(x, y int) bool {
+	return x >= 0 && y >= 0 && x < g.W && y < g.H && !g.blocked[y*g.W+x]
+}
+
+// rebuild recomputes which cells within area are blocked by walls.
+func (g *NavGrid) rebuild(area navRect, walls []*navWall) {
+	x0, y0 := g.cellOf(area.Min)
+	x1, y1 := g.cellOf(area.Max)
+	x0, y0 = clampInt(x0, 0, g.W-1), clampInt(y0, 0, g.H-1)
+	x1, y1 = clampInt(x1, 0, g.W-1), clampInt(y1, 0, g.H-1)
+
+	for y := y0; y <= y1; y++ {
+		for x := x0; x <= x1; x++ {
+			g.blocked[y*g.W+x] = false
+		}
+	}
+	for _, wall := range walls {
+		if wall.passable {
+			continue
+		}
+		r := rectOf(wall.synced, navClearance)
+		wx0, wy0 := g.cellOf(r.Min)
+		wx1, wy1 := g.cellOf(r.Max)
+		wx0, wy0 = clampInt(wx0, x0, x1), clampInt(wy0, y0, y1)
+		wx1, wy1 = clampInt(wx1, x0, x1), clampInt(wy1, y0, y1)
+		for y := wy0; y <= wy1; y++ {
+			for x := wx0; x <= wx1; x++ {
+				if segmentDistance(g.center(x, y), wall.synced) < navClearance {
+					g.blocked[y*g.W+x] = true
+				}
+			}
+		}
+	}
+}
+
+// nearestOpen returns the open cell nearest to (x, y), searching outwards in
+// rings, or false when there is none close by.
+func (g *NavGrid) nearestOpen(x, y int) (int, int, bool) {
+	x, y = clampInt(x, 0, g.W-1), clampInt(y, 0, g.H-1)
+	if g.Open(x, y) {
+		return x, y, true
+	}
+	for r := 1; r <= 8; r++ {
+		for dy := -r; dy <= r; dy++ {
+			for dx := -r; dx <= r; dx++ {
+				if (dx == -r || dx == r || dy == -r || dy == r) && g.Open(x+dx, y+dy) {
+					return x + dx, y + dy, true
+				}
+			}
+		}
+	}
+	return 0, 0, false
+}
+
+// Clear reports whether an enemy can walk in a straight line from a to b
+// without leaving open cells.
+func (g *NavGrid) Clear(a, b engo.Point) bool {
+	dx, dy := b.X-a.X, b.Y-a.Y
+	steps := int(math.Sqrt(dx*dx+dy*dy)/(g.Cell/4)) + 1
+	for i := 0; i <= steps; i++ {
+		t := float32(i) / float32(steps)
+		if !g.Open(g.cellOf(engo.Point{X: a.X + dx*t, Y: a.Y + dy*t})) {
+			return false
+		}
+	}
+	return true
+}
+
+// FindPath finds a way from from to to with A*, and smooths it into as few
+// straight legs as the walls allow. It returns the waypoints after from, the
+// last of which is to (or the nearest reachable point to it), or false when
+// there is no way through.
+func (g *NavGrid) FindPath(from, to engo.Point) ([]engo.Point, bool) {
+	sx, sy, ok := g.nearestOpen(g.cellOf(from))
+	if !ok {
+		return nil, false
+	}
+	cx, cy := g.cellOf(to)
+	tx, ty, ok := g.nearestOpen(cx, cy)
+	if !ok {
+		return nil, false
+	}
+	if tx != cx || ty != cy {
+		// The target itself is out of reach; settle for the nearest cell.
+		to = g.center(tx, ty)
+	}
+
+	start, goal := sy*g.W+sx, ty*g.W+tx
+	cost := make([]float32, len(g.blocked))
+	came := make([]int, len(g.blocked))
+	for i := range cost {
+		cost[i] = -1
+		came[i] = -1
+	}
+	cost[start] = 0
+	open := &navQueue{{cell: start, f: g.heuristic(sx, sy, tx, ty)}}
+
+	found := false
+	for open.Len() > 0 {
+		n := heap.Pop(open).(navNode)
+		if n.cell == goal {
+			found = true
+			break
+		}
+		if n.g > cost[n.cell] {
+			continue // a stale entry for a cell since reached more cheaply
+		}
+		x, y := n.cell%g.W, n.cell/g.W
+		for _, d := range navNeighbours {
+			nx, ny := x+d.dx, y+d.dy
+			if !g.Open(nx, ny) {
+				continue
+			}
+			// No cutting corners past a blocked cell.
+			if d.dx != 0 && d.dy != 0 && (!g.Open(x+d.dx, y) || !g.Open(x, y+d.dy)) {
+				continue
+			}
+			next := ny*g.W + nx
+			c := cost[n.cell] + d.cost*g.Cell
+			if cost[next] >= 0 && c >= cost[next] {
+				continue
+			}
+			cost[next] = c
+			came[next] = n.cell
+			heap.Push(open, navNode{cell: next, g: c, f: c + g.heuristic(nx, ny, tx, ty)})
+		}
+	}
+	if !found {
+		return nil, false
+	}
+
+	// Walk back from the goal to get the cells in order.
+	var cells []engo.Point
+	for c := goal; c != start; c = came[c] {
+		cells = append(cells, g.center(c%g.W, c/g.W))
+	}
+	for i, j := 0, len(cells)-1; i < j; i, j = i+1, j-1 {
+		cells[i], cells[j] = cells[j], cells[i]
+	}
+	if len(cells) == 0 {
+		return []engo.Point{to}, true
+	}
+	cells[len(cells)-1] = to
+	return g.smooth(from, cells), true
+}
+
+// smooth drops every waypoint that can be skipped by walking straight past
+// it, so that paths run diagonally rather than along the grid.
+func (g *NavGrid) smooth(from engo.Point, path []engo.Point) []engo.Point {
+	var out []engo.Point
+	at := from
+	for i := 0; i < len(path); {
+		// Go as far along the path as can be seen from where we are.
+		far := i
+		for j := len(path) - 1; j > i; j-- {
+			if g.Clear(at, path[j]) {
+				far = j
+				break
+			}
+		}
+		out = append(out, path[far])
+		at = path[far]
+		i = far + 1
+	}
+	return out
+}
+
+// heuristic is the octile distance between two cells: the exact cost of the
+// way between them on an open grid.
+func (g *NavGrid) heuristic(x0, y0, x1, y1 int) float32 {
+	dx, dy := float32(absInt(x1-x0)), float32(absInt(y1-y0))
+	return (math.Max(dx, dy) + (math.Sqrt2-1)*math.Min(dx, dy)) * g.Cell
+}
+
+func absInt(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+func clampInt(v, lo, hi int) int {
+	if v < lo {
+		return lo
+	}
+	if v > hi {
+		return hi
+	}
+	return v
+}
+
+var navNeighbours = []struct {
+	dx, dy int
+	cost   float32
+}{
+	{1, 0, 1}, {-1, 0, 1}, {0, 1, 1}, {0, -1, 1},
+	{1, 1, math.Sqrt2}, {1, -1, math.Sqrt2}, {-1, 1, math.Sqrt2}, {-1, -1, math.Sqrt2},
+}
+
+// navNode is a cell waiting in the A* open set, with the cost g it was
+// reached at and its estimated total cost f.
+type navNode struct {
+	cell int
+	g, f float32
+}
+
+// navQueue is a min-heap of navNodes by f.
+type navQueue []navNode
+
+func (q navQueue) Len() int           { return len(q) }
+func (q navQueue) Less(i, j int) bool { return q[i].f < q[j].f }
+func (q navQueue) Swap(i, j int)      { q[i], q[j] = q[j], q[i] }
+func (q *navQueue) Push(x any)        { *q = append(*q, x.(navNode)) }
+
+func (q *navQueue) Pop() any {
+	old := *q
+	n := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return n
+}
+
+// segmentDistance returns the distance from p to the nearest point of l.
+func segmentDistance(p engo.Point, l engo.Line) float32 {
+	return p.PointDistance(nearestPoint(p, l))
+}
+
+// nearestPoint returns the point on the segment l nearest to p.
+func nearestPoint(p engo.Point, l engo.Line) engo.Point {
+	dx, dy := l.P2.X-l.P1.X, l.P2.Y-l.P1.Y
+	t := float32(0)
+	if lenSq := dx*dx + dy*dy; lenSq > 0 {
+		t = math.Clamp(((p.X-l.P1.X)*dx+(p.Y-l.P1.Y)*dy)/lenSq, 0, 1)
+	}
+	return engo.Point{X: l.P1.X + dx*t, Y: l.P1.Y + dy*t}
+}
+
+// navWall is a wall as the grid was last built from it.
+type navWall struct {
+	*ecs.BasicEntity
+	*WallMapComponent
+	synced   engo.Line
+	passable bool
+}
+
+// NavSystem builds a NavGrid from the level's walls on its first tick, and
+// keeps it up to date as doors open and close and walls move or are
+// destroyed, rebuilding only the cells around the walls that changed.
+// Enemies find their way around the level with FindPath.
+//
+//	nav := &systems.NavSystem{}
+//	var navwallable *systems.WallMapAble
+//	w.AddSystemInterface(nav, navwallable, nil)
+type NavSystem struct {
+	// Grid is nil until the first tick.
+	Grid *NavGrid
+
+	walls   []*navWall
+	dirty   []navRect
+	version int
+}
+
+func (s *NavSystem) AddByInterface(i ecs.Identifier) {
+	// MapSystem's minimap entities embed a nil WallMapComponent.
+	o, ok := i.(WallMapAble)
+	if !ok || o.GetWallMapComponent() == nil {
+		return
+	}
+	wall := &navWall{BasicEntity: o.GetBasicEntity(), WallMapComponent: o.GetWallMapComponent()}
+	wall.synced, wall.passable = wall.Wall, wall.Passable
+	s.walls = append(s.walls, wall)
+	s.dirty = append(s.dirty, rectOf(wall.synced, navClearance))
+}
+
+func (s *NavSystem) Remove(basic ecs.BasicEntity) {
+	for i, wall := range s.walls {
+		if wall.ID() == basic.ID() {
+			s.walls = append(s.walls[:i], s.walls[i+1:]...)
+			s.dirty = append(s.dirty, rectOf(wall.synced, navClearance))
+			return
+		}
+	}
+}
+
+// Update does nothing; the grid is kept up to date in Tick.
+func (s *NavSystem) Update(dt float32) {}
+
+func (s *NavSystem) Tick(dt float32) {
+	if s.Grid == nil {
+		if len(s.walls) == 0 {
+			return
+		}
+		bounds := rectOf(s.walls[0].synced, navMargin)
+		for _, wall := range s.walls[1:] {
+			r := rectOf(wall.synced, navMargin)
+			bounds.Min.X, bounds.Min.Y = math.Min(bounds.Min.X, r.Min.X), math.Min(bounds.Min.Y, r.Min.Y)
+			bounds.Max.X, bounds.Max.Y = math.Max(bounds.Max.X, r.Max.X), math.Max(bounds.Max.Y, r.Max.Y)
+		}
+		s.Grid = newNavGrid(bounds, navCellSize)
+		s.Grid.rebuild(bounds, s.walls)
+		s.dirty = nil
+		s.version++
+		return
+	}
+
+	for _, wall := range s.walls {
+		if wall.Wall != wall.synced || wall.Passable != wall.passable {
+			s.dirty = append(s.dirty, rectOf(wall.synced, navClearance), rectOf(wall.Wall, navClearance))
+			wall.synced, wall.passable = wall.Wall, wall.Passable
+		}
+	}
+	if len(s.dirty) == 0 {
+		return
+	}
+	for _, r := range s.dirty {
+		s.Grid.rebuild(r, s.walls)
+	}
+	s.dirty = s.dirty[:0]
+	s.version++
+}
+
+// Version changes whenever the grid does, so that paths planned on an older
+// grid can be planned again.
+func (s *NavSystem) Version() int { return s.version }
+
+// FindPath plans a way from from to to; see NavGrid.FindPath. Before the
+// grid is built it heads straight for to.
+func (s *NavSystem) FindPath(from, to engo.Point) ([]engo.Point, bool) {
+	if s.Grid == nil {
+		return []engo.Point{to}, true
+	}
+	return s.Grid.FindPath(from, to)
+}
+
+// Crosses reports whether walking straight along path would pass through a
+// solid wall.
+func (s *NavSystem) Crosses(path engo.Line) bool {
+	for _, wall := range s.walls {
+		if wall.Passable {
+			continue
+		}
+		if _, ok := engo.LineIntersection(path, wall.Wall); ok {
+			return true
+		}
+	}
+	return false
+}
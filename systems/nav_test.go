@@ -0,0 +1,162 @@
+package systems
+
+import (
+	"testing"
+
+	"github.com/EngoEngine/ecs"
+	"github.com/EngoEngine/engo"
+)
+
+type navTestWall struct {
+	ecs.BasicEntity
+	WallMapComponent
+}
+
+// navTestRoom is a closed 200×200 room.
+var navTestRoom = []engo.Line{
+	{P1: engo.Point{X: 0, Y: 0}, P2: engo.Point{X: 200, Y: 0}},
+	{P1: engo.Point{X: 200, Y: 0}, P2: engo.Point{X: 200, Y: 200}},
+	{P1: engo.Point{X: 200, Y: 200}, P2: engo.Point{X: 0, Y: 200}},
+	{P1: engo.Point{X: 0, Y: 200}, P2: engo.Point{X: 0, Y: 0}},
+}
+
+// newNavTest returns a NavSystem with its grid built from the walls of
+// navTestRoom and extra.
+func newNavTest(extra ...engo.Line) (*NavSystem, []*navTestWall) {
+	s := &NavSystem{}
+	var walls []*navTestWall
+	for _, l := range append(append([]engo.Line(nil), navTestRoom...), extra...) {
+		w := &navTestWall{BasicEntity: ecs.NewBasic()}
+		w.Wall = l
+		s.AddByInterface(w)
+		walls = append(walls, w)
+	}
+	s.Tick(TickDt)
+	return s, walls
+}
+
+// checkPath fails t unless path leads from from to to without crossing a
+// wall.
+func checkPath(t *testing.T, s *NavSystem, from, to engo.Point, path []engo.Point) {
+	t.Helper()
+	if len(path) == 0 {
+		t.Fatal("empty path")
+	}
+	if end := path[len(path)-1]; end != to {
+		t.Errorf("path ends at %v, want %v", end, to)
+	}
+	at := from
+	for _, p := range path {
+		if s.Crosses(engo.Line{P1: at, P2: p}) {
+			t.Errorf("leg %v to %v goes through a wall", at, p)
+		}
+		at = p
+	}
+}
+
+func TestFindPath(t *testing.T) {
+	from, to := engo.Point{X: 50, Y: 100}, engo.Point{X: 150, Y: 100}
+	tests := []struct {
+		name  string
+		walls []engo.Line
+		ok    bool
+		legs  int // most legs the smoothed path may have
+	}{
+		{"open", nil, true, 1},
+		{"around a wall", []engo.Line{{P1: engo.Point{X: 100, Y: 0}, P2: engo.Point{X: 100, Y: 150}}}, true, 3},
+		{"walled off", []engo.Line{{P1: engo.Point{X: 100, Y: 0}, P2: engo.Point{X: 100, Y: 200}}}, false, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, _ := newNavTest(tt.walls...)
+			path, ok := s.FindPath(from, to)
+			if ok != tt.ok {
+				t.Fatalf("FindPath ok = %v, want %v (path %v)", ok, tt.ok, path)
+			}
+			if !ok {
+				return
+			}
+			checkPath(t, s, from, to, path)
+			if len(path) > tt.legs {
+				t.Errorf("path %v has %d legs, want at most %d", path, len(path), tt.legs)
+			}
+		})
+	}
+}
+
+func TestSmooth(t *testing.T) {
+	pt := func(x, y float32) engo.Point { return engo.Point{X: x, Y: y} }
+	tests := []struct {
+		name  string
+		walls []engo.Line
+		path  []engo.Point
+		want  []engo.Point
+	}{
+		{
+			"collinear",
+			nil,
+			[]engo.Point{pt(40, 20), pt(60, 20), pt(80, 20), pt(100, 20)},
+			[]engo.Point{pt(100, 20)},
+		},
+		{
+			"visible corner",
+			nil,
+			[]engo.Point{pt(20, 60), pt(20, 100), pt(60, 100), pt(100, 100)},
+			[]engo.Point{pt(100, 100)},
+		},
+		{
+			"hidden corner",
+			[]engo.Line{{P1: pt(60, 0), P2: pt(60, 80)}},
+			[]engo.Point{pt(20, 100), pt(60, 100), pt(100, 100), pt(100, 20)},
+			[]engo.Point{pt(60, 100), pt(100, 20)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, _ := newNavTest(tt.walls...)
+			got := s.Grid.smooth(pt(20, 20), tt.path)
+			if len(got) != len(tt.want) {
+				t.Fatalf("smoothed to %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("smoothed to %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestNavDoor(t *testing.T) {
+	from, to := engo.Point{X: 50, Y: 100}, engo.Point{X: 150, Y: 100}
+	s, walls := newNavTest(
+		engo.Line{P1: engo.Point{X: 100, Y: 0}, P2: engo.Point{X: 100, Y: 80}},
+		engo.Line{P1: engo.Point{X: 100, Y: 120}, P2: engo.Point{X: 100, Y: 200}},
+		engo.Line{P1: engo.Point{X: 100, Y: 80}, P2: engo.Point{X: 100, Y: 120}},
+	)
+	door := walls[len(walls)-1]
+
+	for _, step := range []struct {
+		name     string
+		passable bool
+		ok       bool
+	}{
+		{"closed", false, false},
+		{"open", true, true},
+		{"closed again", false, false},
+	} {
+		v := s.Version()
+		door.Passable = step.passable
+		s.Tick(TickDt)
+		if changed := s.Version() != v; changed != (step.name != "closed") {
+			t.Errorf("%s: version changed = %v", step.name, changed)
+		}
+		path, ok := s.FindPath(from, to)
+		if ok != step.ok {
+			t.Fatalf("%s: FindPath ok = %v, want %v (path %v)", step.name, ok, step.ok, path)
+		}
+		if ok {
+			checkPath(t, s, from, to, path)
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package systems
+
+import (
+	"container/heap"
+
+	"github.com/EngoEngine/engo"
+	"github.com/EngoEngine/engo/math"
+)
+
+const (
+	// Noise radii in world units: how far each sound carries through open
+	// space to an enemy with normal hearing.
+	gunshotNoise   float32 = 300
+	footstepNoise  float32 = 90
+	footstepStride float32 = 0.35 // seconds between sprinting footsteps
+)
+
+// EnemyState is how aware an enemy is of the player.
+type EnemyState int
+
+const (
+	// EnemyIdle enemies haven't noticed the player, and stand their ground.
+	EnemyIdle EnemyState = iota
+	// EnemyAlert enemies heard something, or lost sight of the player, and
+	// go to where it happened to look around.
+	EnemyAlert
+	// EnemyHunting enemies can see the player, and give chase.
+	EnemyHunting
+)
+
+func (s EnemyState) String() string {
+	switch s {
+	case EnemyIdle:
+		return "idle"
+	case EnemyAlert:
+		return "alert"
+	case EnemyHunting:
+		return "hunting"
+	}
+	return "unknown"
+}
+
+// NoiseMessageType is the engo.Mailbox type of NoiseMessage.
+const NoiseMessageType = "NoiseMessage"
+
+// NoiseMessage is a sound enemies may hear, such as a gunshot or footsteps.
+type NoiseMessage struct {
+	// Pos is where the sound was made, in wall world-space.
+	Pos engo.Point
+	// Radius is how far the sound carries, in world units, going around
+	// walls and through open doors.
+	Radius float32
+}
+
+func (NoiseMessage) Type() string { return NoiseMessageType }
+
+// Senses are an enemy's eyes and ears.
+type Senses struct {
+	// FOV is the full width of the view cone, in degrees.
+	FOV float32
+	// SightRange is how far the enemy can see, in world units.
+	SightRange float32
+	// Hearing scales how far sounds carry to the enemy: 1 is normal, 2
+	// hears things twice as far away.
+	Hearing float32
+}
+
+// DefaultSenses returns the standard enemy senses.
+func DefaultSenses() Senses {
+	return Senses{FOV: 120, SightRange: 400, Hearing: 1}
+}
+
+// Occluder is anything that can block the view, such as NavSystem's walls.
+type Occluder interface {
+	// Crosses reports whether the straight line path is blocked.
+	Crosses(path engo.Line) bool
+}
+
+// CanSee reports whether an eye at from, facing rot degrees (same convention
+// as the player's), sees the point to: it must be within SightRange and the
+// view cone, with nothing in walls in the way. walls may be nil.
+func (s Senses) CanSee(from engo.Point, rot float32, to engo.Point, walls Occluder) bool {
+	dx, dy := to.X-from.X, to.Y-from.Y
+	d := math.Sqrt(dx*dx + dy*dy)
+	if d > s.SightRange {
+		return false
+	}
+	if d > 0 {
+		sin, cos := math.Sincos(rot * math.Pi / 180)
+		// The angle between the facing (sin, -cos) and the way to the point.
+		if (dx*sin-dy*cos)/d < math.Cos(s.FOV/2*math.Pi/180) {
+			return false
+		}
+	}
+	return walls == nil || !walls.Crosses(engo.Line{P1: from, P2: to})
+}
+
+// SoundField is how far a noise has travelled to each part of the level.
+// Sound spreads through the open cells of a NavGrid, so it goes around walls
+// and through open doors but not closed ones.
+type SoundField struct {
+	noise NoiseMessage
+	grid  *NavGrid
+	dist  []float32 // distance the sound travelled to each cell; -1 if it didn't
+	limit float32
+}
+
+// Propagate spreads noise through the grid, as far as limit world units (at
+// least the noise's Radius, more for enemies with keen Hearing). With a nil
+// grid sound travels in straight lines through walls.
+func Propagate(g *NavGrid, noise NoiseMessage, limit float32) *SoundField {
+	f := &SoundField{noise: noise, grid: g, limit: limit}
+	if g == nil {
+		return f
+	}
+	sx, sy, ok := g.nearestOpen(g.cellOf(noise.Pos))
+	f.dist = make([]float32, len(g.blocked))
+	for i := range f.dist {
+		f.dist[i] = -1
+	}
+	if !ok {
+		return f
+	}
+
+	start := sy*g.W + sx
+	f.dist[start] = 0
+	open := &navQueue{{cell: start}}
+	for open.Len() > 0 {
+		n := heap.Pop(open).(navNode)
+		if n.f > f.dist[n.cell] {
+			continue // reached more cheaply since
+		}
+		x, y := n.cell%g.W, n.cell/g.W
+		for _, d := range navNeighbours {
+			nx, ny := x+d.dx, y+d.dy
+			if !g.Open(nx, ny) || (d.dx != 0 && d.dy != 0 && (!g.Open(x+d.dx, y) || !g.Open(x, y+d.dy))) {
+				continue
+			}
+			next := ny*g.W + nx
+			c := n.f + d.cost*g.Cell
+			if c > limit || (f.dist[next] >= 0 && c >= f.dist[next]) {
+				continue
+			}
+			f.dist[next] = c
+			heap.Push(open, navNode{cell: next, f: c})
+		}
+	}
+	return f
+}
+
+// Heard reports whether a listener at p with the given Hearing hears the
+// noise.
+func (f *SoundField) Heard(p engo.Point, hearing float32) bool {
+	reach := math.Min(f.noise.Radius*hearing, f.limit)
+	if f.grid == nil {
+		dx, dy := p.X-f.noise.Pos.X, p.Y-f.noise.Pos.Y
+		return math.Sqrt(dx*dx+dy*dy) <= reach
+	}
+	x, y, ok := f.grid.nearestOpen(f.grid.cellOf(p))
+	if !ok {
+		return false
+	}
+	d := f.dist[y*f.grid.W+x]
+	return d >= 0 && d <= reach
+}
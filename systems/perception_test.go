@@ -0,0 +1,102 @@
+package systems
+
+import (
+	"testing"
+
+	"github.com/EngoEngine/engo"
+	"github.com/EngoEngine/engo/math"
+)
+
+// ahead returns the point d units from the origin, a degrees clockwise from
+// straight ahead for an eye with rotation 0.
+func ahead(a, d float32) engo.Point {
+	sin, cos := math.Sincos(a * math.Pi / 180)
+	return engo.Point{X: d * sin, Y: -d * cos}
+}
+
+func TestCanSee(t *testing.T) {
+	senses := DefaultSenses()
+	open, _ := newNavTest()
+	walled, _ := newNavTest(engo.Line{P1: engo.Point{X: 100, Y: 0}, P2: engo.Point{X: 100, Y: 200}})
+	from := engo.Point{X: 50, Y: 100}
+	toWall := engo.Point{X: 150, Y: 100} // through the wall at x = 100
+
+	tests := []struct {
+		name  string
+		from  engo.Point
+		rot   float32
+		to    engo.Point
+		walls Occluder
+		want  bool
+	}{
+		{"straight ahead", engo.Point{}, 0, ahead(0, 100), nil, true},
+		{"behind", engo.Point{}, 0, ahead(180, 100), nil, false},
+		{"to the side", engo.Point{}, 0, ahead(90, 100), nil, false},
+		{"inside the cone edge", engo.Point{}, 0, ahead(senses.FOV/2-1, 100), nil, true},
+		{"outside the cone edge", engo.Point{}, 0, ahead(senses.FOV/2+1, 100), nil, false},
+		{"other side of the cone", engo.Point{}, 0, ahead(-senses.FOV/2+1, 100), nil, true},
+		{"turned", engo.Point{}, 90, ahead(90, 100), nil, true},
+		{"in range", engo.Point{}, 0, ahead(0, senses.SightRange-1), nil, true},
+		{"beyond SightRange", engo.Point{}, 0, ahead(0, senses.SightRange+1), nil, false},
+		{"same spot", engo.Point{}, 0, engo.Point{}, nil, true},
+		{"clear room", from, 90, toWall, open, true},
+		{"behind a wall", from, 90, toWall, walled, false},
+		{"no occluder", from, 90, toWall, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := senses.CanSee(tt.from, tt.rot, tt.to, tt.walls); got != tt.want {
+				t.Errorf("CanSee = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHearing(t *testing.T) {
+	noise := engo.Point{X: 50, Y: 100}
+	listener := engo.Point{X: 150, Y: 100} // 100 away in a straight line
+
+	// A wall between the two with a way round at the far end, about 160
+	// long.
+	corner, _ := newNavTest(engo.Line{P1: engo.Point{X: 100, Y: 0}, P2: engo.Point{X: 100, Y: 150}})
+
+	tests := []struct {
+		name   string
+		grid   *NavGrid
+		radius float32
+		want   bool
+	}{
+		{"around a corner", corner.Grid, 200, true},
+		{"not far enough around a corner", corner.Grid, 120, false},
+		{"straight through walls without a grid", nil, 120, true},
+		{"too far without a grid", nil, 90, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := Propagate(tt.grid, NoiseMessage{Pos: noise, Radius: tt.radius}, tt.radius)
+			if got := f.Heard(listener, 1); got != tt.want {
+				t.Errorf("Heard = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHearingThroughDoor(t *testing.T) {
+	noise := NoiseMessage{Pos: engo.Point{X: 50, Y: 100}, Radius: 300}
+	listener := engo.Point{X: 150, Y: 100}
+	s, walls := newNavTest(
+		engo.Line{P1: engo.Point{X: 100, Y: 0}, P2: engo.Point{X: 100, Y: 80}},
+		engo.Line{P1: engo.Point{X: 100, Y: 120}, P2: engo.Point{X: 100, Y: 200}},
+		engo.Line{P1: engo.Point{X: 100, Y: 80}, P2: engo.Point{X: 100, Y: 120}},
+	)
+	door := walls[len(walls)-1]
+
+	if Propagate(s.Grid, noise, noise.Radius).Heard(listener, 1) {
+		t.Error("heard through a closed door")
+	}
+	door.Passable = true
+	s.Tick(TickDt)
+	if !Propagate(s.Grid, noise, noise.Radius).Heard(listener, 1) {
+		t.Error("not heard through an open door")
+	}
+}
@@ -17,6 +17,49 @@ const (
 	projectileLifetime float32 = 3.0  // seconds before despawn
 	projectileSize     float32 = 8.0  // billboard width/height
 	projectileRadius   float32 = 10.0 // collision detection radius
+	projectileDamage   float32 = 25   // damage dealt on impact when Damage is unset
+	projectileMuzzle   float32 = 15   // how far in front of the shooter projectiles appear
+
+	// Defaults for unset ProjectileBehaviour fields.
+	projectileRestitution float32 = 0.8 // fraction of its speed a projectile keeps on bouncing
+	projectileHomingCone  float32 = 90  // degrees
+	projectileHomingRange float32 = 300 // world units
+	projectileSplitSpread float32 = 60  // degrees
+)
+
+// ProjectileBehaviour is how a projectile flies and what it does when it
+// hits something. Each is off when zero, and they can be combined: a
+// projectile that bounces, pierces and splits goes through its Pierce
+// targets and off its Bounces walls before it splits.
+type ProjectileBehaviour struct {
+	// Bounces is how many walls the projectile bounces off, keeping
+	// Restitution of its speed each time, before it hits one for good.
+	// Restitution is initialised to projectileRestitution when zero.
+	Bounces     int
+	Restitution float32
+	// Pierce is how many targets the projectile goes through, hurting each,
+	// before it stops in one.
+	Pierce int
+	// Homing is how fast the projectile turns toward the nearest target in
+	// sight, within HomingRange and the HomingCone ahead of it, in degrees
+	// per second. HomingCone, the full width of the cone in degrees, and
+	// HomingRange are initialised to projectileHomingCone and
+	// projectileHomingRange when zero.
+	Homing, HomingCone, HomingRange float32
+	// Split is how many projectiles this one splits into when it finally
+	// hits a wall or someone, fanned out over SplitSpread degrees. They fly
+	// on as this one did, but don't split again. SplitSpread is initialised
+	// to projectileSplitSpread when zero.
+	Split       int
+	SplitSpread float32
+}
+
+// Faction is the side something fights on.
+type Faction int
+
+const (
+	FactionPlayer Faction = iota
+	FactionEnemy
 )
 
 // ProjectileComponent holds all data for a projectile entity.
@@ -27,10 +70,39 @@ type ProjectileComponent struct {
 	Lifetime float32
 	// Tex is the texture shown on the 3D billboard. Nil renders a solid colour.
 	Tex *gl.Texture
+	// Damage is dealt to whatever the projectile hits. It is initialised to
+	// projectileDamage by ProjectileSystem when zero.
+	Damage float32
+	// Owner is the ID of the entity that fired the projectile, which it
+	// never hits. Zero is nobody.
+	Owner uint64
+	// Faction is the side that fired the projectile. Unless the
+	// ProjectileSystem allows FriendlyFire it only hits the other side.
+	Faction Faction
+	// Origin is where the projectile was fired from, in wall world-space.
+	// The player's HUD shows which way hits came from.
+	Origin engo.Point
+	// Climb is the upward velocity in world units per second, which
+	// Gravity, in world units per second², takes away from; projectiles
+	// without Gravity fly flat. The projectile's SpaceComponent Height is
+	// how high it is above the level's base floor.
+	Climb, Gravity float32
+	// Stick is how many seconds the projectile stays stuck in the wall or
+	// floor it hits. Zero makes it vanish on impact.
+	Stick float32
+	ProjectileBehaviour
+
+	stuck   bool     // stuck in a wall or the floor
+	stuckIn uint64   // ID of the wall it is stuck in; zero for the floor
+	passed  []uint64 // targets it has pierced, which it can't hit again
 }
 
 func (c *ProjectileComponent) GetProjectileComponent() *ProjectileComponent { return c }
 
+// Stuck reports whether the projectile has hit a wall or the floor and
+// stuck there.
+func (c *ProjectileComponent) Stuck() bool { return c.stuck }
+
 // ProjectileFace is the minimal interface for the ProjectileComponent accessor.
 type ProjectileFace interface {
 	GetProjectileComponent() *ProjectileComponent
@@ -53,6 +125,7 @@ type projectileEntity struct {
 	*ecs.BasicEntity
 	*common.SpaceComponent
 	*ProjectileComponent
+	*InterpolatedComponent
 
 	// billboard is the 3D view entity; uses shaders.ViewShader.
 	billboard struct {
@@ -69,13 +142,37 @@ type projectileEntity struct {
 	}
 }
 
+// projectileWallEntity is a wall projectiles can strike. destructible is nil
+// for walls that can't be damaged.
+type projectileWallEntity struct {
+	*ecs.BasicEntity
+	*WallMapComponent
+	destructible *DestructibleComponent
+}
+
 // ProjectileSystem manages projectile entities. It tracks the player to know
 // their position for spawning new projectiles and handles projectile physics,
-// collision, and despawning.
+// collision, and despawning. It also accepts WallMapAble entities so that
+// projectiles stop at walls and damage those with a DestructibleComponent,
+// and EnemyAble entities, which projectiles Deal their Damage to. Projectiles hit
+// the player and enemies of the other Faction, or of either with
+// FriendlyFire, but never whoever fired them. Projectiles with Gravity fall
+// in an arc, and those that Stick stay in the wall or floor they hit for a
+// while.
 type ProjectileSystem struct {
-	w           *ecs.World
-	player      *common.SpaceComponent
-	projectiles []*projectileEntity
+	// FriendlyFire lets projectiles hit their own side.
+	FriendlyFire bool
+	// Floors is what falling projectiles land on. Without it they land on
+	// the base floor.
+	Floors *FloorSystem
+
+	w             *ecs.World
+	playerID      uint64
+	player        *common.SpaceComponent
+	playerControl *ControlComponent
+	projectiles   []*projectileEntity
+	walls         []projectileWallEntity
+	enemies       []enemyTarget
 }
 
 func (s *ProjectileSystem) New(w *ecs.World) {
@@ -85,7 +182,32 @@ func (s *ProjectileSystem) New(w *ecs.World) {
 func (s *ProjectileSystem) AddByInterface(i ecs.Identifier) {
 	// Accept the player entity so we can track their position/rotation.
 	if o, ok := i.(ViewPlayerAble); ok {
+		s.playerID = o.GetBasicEntity().ID()
 		s.player = o.GetSpaceComponent()
+		s.playerControl = o.GetControlComponent()
+		return
+	}
+
+	// Accept enemies as targets.
+	if o, ok := i.(EnemyAble); ok {
+		s.enemies = append(s.enemies, enemyTarget{o.GetBasicEntity(), o.GetSpaceComponent(), o.GetEnemyComponent()})
+		return
+	}
+
+	// Accept walls. MapSystem's minimap entities embed a nil
+	// WallMapComponent, so skip anything without real geometry.
+	if o, ok := i.(WallMapAble); ok {
+		if o.GetWallMapComponent() == nil {
+			return
+		}
+		wall := projectileWallEntity{
+			BasicEntity:      o.GetBasicEntity(),
+			WallMapComponent: o.GetWallMapComponent(),
+		}
+		if d, ok := i.(DestructibleFace); ok {
+			wall.destructible = d.GetDestructibleComponent()
+		}
+		s.walls = append(s.walls, wall)
 		return
 	}
 
@@ -97,6 +219,21 @@ func (s *ProjectileSystem) AddByInterface(i ecs.Identifier) {
 
 	pc := o.GetProjectileComponent()
 	sp := o.GetSpaceComponent()
+	if pc.Damage == 0 {
+		pc.Damage = projectileDamage
+	}
+	if pc.Restitution == 0 {
+		pc.Restitution = projectileRestitution
+	}
+	if pc.HomingCone == 0 {
+		pc.HomingCone = projectileHomingCone
+	}
+	if pc.HomingRange == 0 {
+		pc.HomingRange = projectileHomingRange
+	}
+	if pc.SplitSpread == 0 {
+		pc.SplitSpread = projectileSplitSpread
+	}
 
 	proj := &projectileEntity{
 		BasicEntity:         o.GetBasicEntity(),
@@ -116,13 +253,18 @@ func (s *ProjectileSystem) AddByInterface(i ecs.Identifier) {
 			Pos: sp.Position,
 			W:   projectileSize,
 			H:   projectileSize,
+			Z:   defaultWallHeight - projectileSize/2 - sp.Height,
 			Tex: pc.Tex,
 		},
 		Color: color.RGBA{0xff, 0xff, 0xff, 0xff},
 	}
 	if pc.Tex == nil {
-		// No texture: render as a solid red sphere.
+		// No texture: render as a solid sphere, red for the player's and
+		// green for the enemies'.
 		proj.billboard.RenderComponent.Color = color.RGBA{0xff, 0x33, 0x00, 0xff}
+		if pc.Faction == FactionEnemy {
+			proj.billboard.RenderComponent.Color = color.RGBA{0x66, 0xff, 0x33, 0xff}
+		}
 	}
 	proj.billboard.SetShader(shaders.ViewShader)
 	s.w.AddEntity(&proj.billboard)
@@ -149,33 +291,38 @@ func (s *ProjectileSystem) AddByInterface(i ecs.Identifier) {
 	s.projectiles = append(s.projectiles, proj)
 }
 
+// Remove despawns the projectile's billboard and minimap dot along with it.
 func (s *ProjectileSystem) Remove(basic ecs.BasicEntity) {
+	if s.player != nil && s.playerID == basic.ID() {
+		s.player = nil
+		s.playerControl = nil
+		return
+	}
 	for i, proj := range s.projectiles {
 		if proj.BasicEntity.ID() == basic.ID() {
-			// Remove billboard and map dot from the world
-			for _, sys := range s.w.Systems() {
-				sys.Remove(proj.billboard.BasicEntity)
-				sys.Remove(proj.mapDot.BasicEntity)
-			}
+			despawn(s.w, proj.billboard.BasicEntity, &proj.billboard.RenderComponent)
+			despawn(s.w, proj.mapDot.BasicEntity, &proj.mapDot.RenderComponent)
 			s.projectiles = append(s.projectiles[:i], s.projectiles[i+1:]...)
 			return
 		}
 	}
-}
-
-func (s *ProjectileSystem) Update(dt float32) {
-	if s.player == nil {
-		return
+	for i, wall := range s.walls {
+		if wall.BasicEntity.ID() == basic.ID() {
+			s.walls = append(s.walls[:i], s.walls[i+1:]...)
+			return
+		}
 	}
+	for i, e := range s.enemies {
+		if e.BasicEntity.ID() == basic.ID() {
+			s.enemies = append(s.enemies[:i], s.enemies[i+1:]...)
+			return
+		}
+	}
+}
 
-	const near float32 = 1.0
-	po := shaders.PlayerOffset
-	playerX := s.player.Position.X - po.X
-	playerY := s.player.Position.Y - po.Y
-
-	sin, cos := math.Sincos(s.player.Rotation * math.Pi / 180)
-
-	// Process each projectile
+// Tick moves projectiles, bouncing, piercing, homing and splitting as they
+// do, and despawns those that expire or hit a wall or someone.
+func (s *ProjectileSystem) Tick(dt float32) {
 	for i := len(s.projectiles) - 1; i >= 0; i-- {
 		proj := s.projectiles[i]
 
@@ -183,20 +330,294 @@ func (s *ProjectileSystem) Update(dt float32) {
 		proj.Lifetime -= dt
 		if proj.Lifetime <= 0 {
 			// Despawn projectile
-			s.Remove(*proj.BasicEntity)
+			s.w.RemoveEntity(*proj.BasicEntity)
 			continue
 		}
 
+		if proj.stuck {
+			// Stuck projectiles fall out of walls that open or break.
+			if proj.stuckIn != 0 && !s.solid(proj.stuckIn) {
+				s.w.RemoveEntity(*proj.BasicEntity)
+			}
+			continue
+		}
+
+		if proj.Homing > 0 {
+			s.home(proj, dt)
+		}
+
 		// ── Move projectile ──────────────────────────────────────────────
+		from := proj.SpaceComponent.Position
 		proj.SpaceComponent.Position.X += proj.Velocity.X * dt
 		proj.SpaceComponent.Position.Y += proj.Velocity.Y * dt
+		proj.Climb -= proj.Gravity * dt
+		proj.SpaceComponent.Height += proj.Climb * dt
+
+		// ── Impact ───────────────────────────────────────────────────────
+		// Whatever the projectile reaches first along its path this tick
+		// takes the hit.
+		path := engo.Line{P1: from, P2: proj.SpaceComponent.Position}
+		wall, at, wallDist := s.nearestWall(path)
+		if target, ok := s.hitTarget(proj, path, wall, wallDist); ok {
+			proj.passed = append(proj.passed, target)
+			if proj.Pierce <= 0 {
+				s.split(proj, path.P2, proj.Velocity)
+				s.w.RemoveEntity(*proj.BasicEntity)
+				continue
+			}
+			proj.Pierce--
+		}
+		if wall >= 0 {
+			// Note the wall before damaging it, which may remove it from
+			// s.walls.
+			line, id := s.walls[wall].Wall, s.walls[wall].ID()
+			if s.hitWall(wall, proj.Damage) {
+				// Spent breaking the wall; there is nothing left to bounce
+				// off or stick in.
+				s.split(proj, at, proj.Velocity)
+				s.w.RemoveEntity(*proj.BasicEntity)
+				continue
+			}
+			if proj.Bounces > 0 {
+				s.bounce(proj, at, line)
+				continue
+			}
+			s.split(proj, at, bounceOff(proj.Velocity, line))
+			s.land(proj, at, id)
+			continue
+		}
+		ground := float32(0)
+		if s.Floors != nil {
+			ground = s.Floors.GroundAt(proj.SpaceComponent.Position)
+		}
+		if proj.SpaceComponent.Height <= ground {
+			proj.SpaceComponent.Height = ground
+			s.land(proj, proj.SpaceComponent.Position, 0)
+		}
+	}
+}
+
+// land ends proj's flight at p, in the wall with ID wall or the floor if
+// zero: it sticks there for its Stick time, or vanishes if it doesn't stick.
+func (s *ProjectileSystem) land(proj *projectileEntity, p engo.Point, wall uint64) {
+	if proj.Stick <= 0 {
+		s.w.RemoveEntity(*proj.BasicEntity)
+		return
+	}
+	// Back off a little, so that the projectile shows on the side of the
+	// wall it hit.
+	dir, _ := proj.Velocity.Normalize()
+	proj.SpaceComponent.Position = engo.Point{X: p.X - dir.X, Y: p.Y - dir.Y}
+	proj.Velocity, proj.Climb, proj.Gravity = engo.Point{}, 0, 0
+	proj.Lifetime = proj.Stick
+	proj.stuck, proj.stuckIn = true, wall
+}
+
+// bounce sends proj back off wall, which it hit at p, a little slower.
+func (s *ProjectileSystem) bounce(proj *projectileEntity, p engo.Point, wall engo.Line) {
+	dir, _ := proj.Velocity.Normalize()
+	proj.SpaceComponent.Position = engo.Point{X: p.X - dir.X, Y: p.Y - dir.Y}
+	v := bounceOff(proj.Velocity, wall)
+	proj.Velocity = engo.Point{X: v.X * proj.Restitution, Y: v.Y * proj.Restitution}
+	proj.Bounces--
+}
+
+// bounceOff returns v bounced off wall.
+func bounceOff(v engo.Point, wall engo.Line) engo.Point {
+	n := wall.Normal()
+	d := 2 * (v.X*n.X + v.Y*n.Y)
+	return engo.Point{X: v.X - d*n.X, Y: v.Y - d*n.Y}
+}
+
+// split breaks proj, which hit something at p, into its Split projectiles,
+// fanned out around dir. They start just short of p.
+func (s *ProjectileSystem) split(proj *projectileEntity, p, dir engo.Point) {
+	if proj.Split <= 0 {
+		return
+	}
+	back, _ := proj.Velocity.Normalize()
+	p = engo.Point{X: p.X - back.X, Y: p.Y - back.Y}
+	speed := math.Sqrt(proj.Velocity.X*proj.Velocity.X + proj.Velocity.Y*proj.Velocity.Y)
+	heading := math.Atan2(dir.Y, dir.X)
+	spread := proj.SplitSpread * math.Pi / 180
+	for i := 0; i < proj.Split; i++ {
+		a := heading
+		if proj.Split > 1 {
+			a += spread * (float32(i)/float32(proj.Split-1) - 0.5)
+		}
+		sin, cos := math.Sincos(a)
+		pc := *proj.ProjectileComponent
+		pc.Velocity = engo.Point{X: cos * speed, Y: sin * speed}
+		pc.Split = 0
+		pc.passed = append([]uint64(nil), proj.passed...)
+		s.spawn(p, proj.SpaceComponent.Height, pc)
+	}
+}
 
+// home turns proj toward the nearest target it may hit that it can see
+// within its HomingRange and HomingCone, by up to its Homing rate.
+func (s *ProjectileSystem) home(proj *projectileEntity, dt float32) {
+	pc := proj.ProjectileComponent
+	pos := proj.SpaceComponent.Position
+	heading := math.Atan2(proj.Velocity.Y, proj.Velocity.X)
+	halfCone := pc.HomingCone / 2 * math.Pi / 180
+	best := pc.HomingRange
+	var turn float32
+	found := false
+	consider := func(id uint64, p engo.Point) {
+		if pc.hasPassed(id) {
+			return
+		}
+		dx, dy := p.X-pos.X, p.Y-pos.Y
+		d := math.Sqrt(dx*dx + dy*dy)
+		if d > best {
+			return
+		}
+		off := angleDiff(math.Atan2(dy, dx), heading)
+		if math.Abs(off) > halfCone {
+			return
+		}
+		if wall, _, _ := s.nearestWall(engo.Line{P1: pos, P2: p}); wall >= 0 {
+			return
+		}
+		best, turn, found = d, off, true
+	}
+	if s.player != nil && s.playerControl != nil && s.hurts(pc, s.playerID, FactionPlayer) {
+		consider(s.playerID, groundPos(s.player.Position))
+	}
+	for _, e := range s.enemies {
+		if s.hurts(pc, e.BasicEntity.ID(), FactionEnemy) {
+			consider(e.BasicEntity.ID(), e.Position)
+		}
+	}
+	if !found {
+		return
+	}
+	limit := pc.Homing * math.Pi / 180 * dt
+	turn = math.Clamp(turn, -limit, limit)
+	sin, cos := math.Sincos(turn)
+	v := proj.Velocity
+	proj.Velocity = engo.Point{X: v.X*cos - v.Y*sin, Y: v.X*sin + v.Y*cos}
+}
+
+// angleDiff returns a-b in radians, wrapped to between -π and π.
+func angleDiff(a, b float32) float32 {
+	d := math.Mod(a-b, 2*math.Pi)
+	switch {
+	case d > math.Pi:
+		d -= 2 * math.Pi
+	case d < -math.Pi:
+		d += 2 * math.Pi
+	}
+	return d
+}
+
+// hasPassed reports whether the projectile has already pierced id.
+func (c *ProjectileComponent) hasPassed(id uint64) bool {
+	for _, p := range c.passed {
+		if p == id {
+			return true
+		}
+	}
+	return false
+}
+
+// solid reports whether the wall with ID id is still standing and closed.
+func (s *ProjectileSystem) solid(id uint64) bool {
+	for _, wall := range s.walls {
+		if wall.ID() == id {
+			return !wall.Passable
+		}
+	}
+	return false
+}
+
+// hurts reports whether pc may hit the entity id fighting for faction f.
+func (s *ProjectileSystem) hurts(pc *ProjectileComponent, id uint64, f Faction) bool {
+	if id == pc.Owner {
+		return false
+	}
+	return pc.Faction != f || s.FriendlyFire
+}
+
+// hitTarget deals proj's damage to the first player or enemy that it passes
+// within projectileRadius of along path, other than those it has already
+// pierced, if it is closer than the wall struck at squared distance wallDist
+// (when wall >= 0), and returns which one, if any.
+func (s *ProjectileSystem) hitTarget(proj *projectileEntity, path engo.Line, wall int, wallDist float32) (uint64, bool) {
+	pc := proj.ProjectileComponent
+	best, limited := wallDist, wall >= 0
+	var player bool
+	var enemy *enemyTarget
+	// closer reports whether p is in the way, and closer than anything yet.
+	closer := func(p engo.Point) bool {
+		if segmentDistance(p, path) > projectileRadius {
+			return false
+		}
+		d := path.P1.PointDistanceSquared(p)
+		if limited && d >= best {
+			return false
+		}
+		best, limited = d, true
+		return true
+	}
+
+	// Projectiles fly over the heads of those below them. The player's head
+	// is a little above their eye.
+	low := proj.SpaceComponent.Height - projectileSize/2
+	if s.player != nil && s.playerControl != nil && s.hurts(pc, s.playerID, FactionPlayer) &&
+		!pc.hasPassed(s.playerID) && low <= eyeHeight(s.player)+projectileSize {
+		player = closer(groundPos(s.player.Position))
+	}
+	for i := range s.enemies {
+		e := &s.enemies[i]
+		id := e.BasicEntity.ID()
+		if s.hurts(pc, id, FactionEnemy) && !pc.hasPassed(id) && low <= e.H && closer(e.Position) {
+			player, enemy = false, e
+		}
+	}
+
+	hit := Damage{
+		Amount:      pc.Damage,
+		Type:        DamageProjectile,
+		Source:      pc.Owner,
+		From:        pc.Origin,
+		Directional: true,
+	}
+	switch {
+	case enemy != nil:
+		id := enemy.BasicEntity.ID()
+		Deal(id, &enemy.Health, &enemy.Defense, hit)
+		return id, true
+	case player:
+		Deal(s.playerID, &s.playerControl.Health, &s.playerControl.Defense, hit)
+		return s.playerID, true
+	}
+	return 0, false
+}
+
+// Update places each projectile's billboard and map dot where it is drawn
+// this frame, which is between ticks for interpolated projectiles.
+func (s *ProjectileSystem) Update(dt float32) {
+	if s.player == nil {
+		return
+	}
+
+	const near float32 = 1.0
+	po := shaders.PlayerOffset
+	playerX := s.player.Position.X - po.X
+	playerY := s.player.Position.Y - po.Y
+
+	sin, cos := math.Sincos(s.player.Rotation * math.Pi / 180)
+
+	for _, proj := range s.projectiles {
 		// Update billboard and map dot positions
 		proj.billboard.SpaceComponent.Position = proj.SpaceComponent.Position
 		proj.billboard.Drawable = shaders.Billboard{
 			Pos: proj.SpaceComponent.Position,
 			W:   projectileSize,
 			H:   projectileSize,
+			Z:   defaultWallHeight - projectileSize/2 - proj.SpaceComponent.Height,
 			Tex: proj.Tex,
 		}
 		proj.mapDot.SpaceComponent.Position = engo.Point{
@@ -228,43 +649,105 @@ func (s *ProjectileSystem) Update(dt float32) {
 	}
 }
 
-// SpawnProjectile creates a new projectile at the player's position,
-// fired in the direction the player is facing.
-func (s *ProjectileSystem) SpawnProjectile(tex *gl.Texture) {
+// nearestWall returns the index of the nearest wall path crosses, where it
+// crosses it and the squared distance to there along path. The index is -1
+// if there is none.
+func (s *ProjectileSystem) nearestWall(path engo.Line) (int, engo.Point, float32) {
+	hit := -1
+	var at engo.Point
+	var best float32
+	for i, wall := range s.walls {
+		if wall.Passable {
+			continue
+		}
+		p, ok := engo.LineIntersection(path, wall.Wall)
+		if !ok {
+			continue
+		}
+		if d := path.P1.PointDistanceSquared(p); hit < 0 || d < best {
+			hit, at, best = i, p, d
+		}
+	}
+	return hit, at, best
+}
+
+// hitWall damages wall i, removing it from the world and reporting true if
+// that destroys it.
+func (s *ProjectileSystem) hitWall(i int, damage float32) bool {
+	wall := s.walls[i]
+	if wall.destructible != nil && wall.destructible.Damage(damage) {
+		s.w.RemoveEntity(*wall.BasicEntity)
+		return true
+	}
+	return false
+}
+
+// SpawnProjectile fires one of ammo's shots from the player's eye, in the
+// direction they are facing. Its speed and damage are strength, from 0 to 1,
+// of those of a fully charged shot.
+func (s *ProjectileSystem) SpawnProjectile(ammo *Ammunition, strength float32) {
 	if s.player == nil {
 		return
 	}
 
-	po := shaders.PlayerOffset
-	// Spawn slightly in front of the player to avoid self-collision
-	spawnOffset := float32(15.0)
-
+	pos := groundPos(s.player.Position)
 	sin, cos := math.Sincos(s.player.Rotation * math.Pi / 180)
+	speed, damage := ammo.Speed, ammo.Damage
+	if speed == 0 {
+		speed = projectileSpeed
+	}
+	if damage == 0 {
+		damage = projectileDamage
+	}
+	s.Fire(pos, eyeHeight(s.player), engo.Point{X: sin, Y: -cos}, speed*strength, ProjectileComponent{
+		Tex:     ammo.ProjectileTex,
+		Damage:  damage * strength,
+		Owner:   s.playerID,
+		Faction: FactionPlayer,
+		Gravity: ammo.Gravity,
+		Stick:   ammo.Stick,
+
+		ProjectileBehaviour: ammo.ProjectileBehaviour,
+	})
+
+	// The shot is heard from where the player stands.
+	engo.Mailbox.Dispatch(NoiseMessage{Pos: pos, Radius: gunshotNoise})
+}
 
-	// Calculate spawn position (player position + offset in facing direction)
-	spawnX := (s.player.Position.X - po.X) + spawnOffset*sin
-	spawnY := (s.player.Position.Y - po.Y) - spawnOffset*cos
-
-	// Calculate velocity vector in facing direction
-	velX := projectileSpeed * sin
-	velY := -projectileSpeed * cos
+// Fire launches a projectile from origin, in wall world-space, height above
+// the base floor, flat along dir. It appears a little way in front of
+// origin, moving at speed, or projectileSpeed if zero; pc's Velocity and
+// Origin are filled in, and its Lifetime if zero.
+func (s *ProjectileSystem) Fire(origin engo.Point, height float32, dir engo.Point, speed float32, pc ProjectileComponent) {
+	if speed == 0 {
+		speed = projectileSpeed
+	}
+	dir, _ = dir.Normalize()
+	pc.Origin = origin
+	pc.Velocity = engo.Point{X: dir.X * speed, Y: dir.Y * speed}
+	if pc.Lifetime == 0 {
+		pc.Lifetime = projectileLifetime
+	}
+	s.spawn(engo.Point{
+		X: origin.X + dir.X*projectileMuzzle,
+		Y: origin.Y + dir.Y*projectileMuzzle,
+	}, height, pc)
+}
 
-	// Create projectile entity
+// spawn adds a projectile at pos, in wall-space, and height above the base
+// floor to the world: this system creates its billboard and map dot, and
+// other systems (e.g. TeleportSystem) get to track it too.
+func (s *ProjectileSystem) spawn(pos engo.Point, height float32, pc ProjectileComponent) {
 	e := &projectileEntity{
 		BasicEntity: new(ecs.BasicEntity),
 		SpaceComponent: &common.SpaceComponent{
-			Position: engo.Point{X: spawnX, Y: spawnY},
+			Position: pos,
 			Width:    projectileSize,
-			Height:   projectileSize,
-		},
-		ProjectileComponent: &ProjectileComponent{
-			Velocity: engo.Point{X: velX, Y: velY},
-			Lifetime: projectileLifetime,
-			Tex:      tex,
+			Height:   height,
 		},
+		ProjectileComponent:   &pc,
+		InterpolatedComponent: &InterpolatedComponent{},
 	}
 	*e.BasicEntity = ecs.NewBasic()
-
-	// Add it to the system (which will create billboard and map dot)
-	s.AddByInterface(e)
+	s.w.AddEntity(e)
 }
@@ -0,0 +1,13 @@
+package systems
+
+import "math/rand"
+
+// Rand is the random number generator for everything that affects gameplay.
+// Gameplay code must draw from it rather than from the global math/rand
+// functions, so that a demo recorded with a given seed plays back the same.
+var Rand = rand.New(rand.NewSource(1))
+
+// SeedRand reseeds Rand. Scenes call it before adding any entities.
+func SeedRand(seed int64) {
+	Rand.Seed(seed)
+}
@@ -0,0 +1,794 @@
+package systems
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/EngoEngine/ecs"
+	"github.com/EngoEngine/engo"
+	"github.com/EngoEngine/engo/common"
+
+	"github.com/SkeleboyStudios/SkeleDoom/shaders"
+)
+
+// SaveVersion is the version of the save file format written by this build.
+// Bump it whenever SaveGame changes shape or meaning, and add a migration
+// from the previous version to saveMigrations.
+const SaveVersion = 9
+
+// saveMigrations[v] upgrades a decoded version v save file to version v+1.
+// Versions without an entry can't be loaded.
+var saveMigrations = map[int]func(save map[string]any) error{
+	// Version 2 added enemies; there were none before.
+	1: func(save map[string]any) error {
+		save["Enemies"] = []any{}
+		return nil
+	},
+	// Version 3 gave enemies senses. Before, they always knew where the
+	// player was.
+	2: func(save map[string]any) error {
+		player, _ := save["Player"].(map[string]any)
+		pos, _ := player["Position"].(map[string]any)
+		x, _ := pos["X"].(float64)
+		y, _ := pos["Y"].(float64)
+		po := shaders.PlayerOffset
+		for _, e := range saveObjects(save["Enemies"]) {
+			e["State"] = EnemyHunting
+			e["LastKnown"] = map[string]any{"X": x - float64(po.X), "Y": y - float64(po.Y)}
+			e["Search"] = 0
+		}
+		return nil
+	},
+	// Version 4 gave projectiles owners. Before, only the player shot.
+	3: func(save map[string]any) error {
+		for _, p := range saveObjects(save["Projectiles"]) {
+			p["Owner"] = saveOwnerPlayer
+			p["Faction"] = FactionPlayer
+			p["Origin"] = p["Position"]
+		}
+		return nil
+	},
+	// Version 5 added armor, which nobody had before.
+	4: func(save map[string]any) error {
+		if player, ok := save["Player"].(map[string]any); ok {
+			player["Armor"] = 0
+		}
+		return nil
+	},
+	// Version 6 let enemies' attacks grow stronger, in waves. Before, they
+	// all did the standard damage.
+	5: func(save map[string]any) error {
+		for _, e := range saveObjects(save["Enemies"]) {
+			e["AttackDamage"] = enemyAttackDamage
+			e["MeleeDamage"] = enemyMeleeDamage
+		}
+		return nil
+	},
+	// Version 7 added bombs.
+	6: func(save map[string]any) error {
+		save["Bombs"] = []any{}
+		if player, ok := save["Player"].(map[string]any); ok {
+			player["Bombs"] = 0
+		}
+		return nil
+	},
+	// Version 8 added weapons, and projectiles flying at a height. Before,
+	// the player had the one weapon, and projectiles flew flat at the
+	// height they were fired from.
+	7: func(save map[string]any) error {
+		player, _ := save["Player"].(map[string]any)
+		if player != nil {
+			player["Weapon"] = 0
+		}
+		eye, _ := player["NormalHeight"].(float64)
+		for _, p := range saveObjects(save["Projectiles"]) {
+			p["Height"] = float64(defaultWallHeight) - eye
+			if f, _ := p["Faction"].(float64); Faction(f) == FactionEnemy {
+				p["Height"] = enemyMuzzleHeight
+			}
+			p["Weapon"] = 0
+		}
+		return nil
+	},
+	// Version 9 gave projectiles behaviours; they all used to stop at the
+	// first thing they hit.
+	8: func(save map[string]any) error {
+		for _, p := range saveObjects(save["Projectiles"]) {
+			p["Bounces"] = 0
+			p["Pierce"] = 0
+			p["Homing"] = 0
+			p["Split"] = 0
+		}
+		return nil
+	},
+}
+
+// Owners of saved projectiles and bombs, besides enemies, which are numbered from 1 in
+// the order they are saved.
+const (
+	saveOwnerNone   = 0
+	saveOwnerPlayer = -1
+)
+
+// saveObjects returns the JSON objects in list, a decoded array, for
+// migrations to update in place.
+func saveObjects(list any) []map[string]any {
+	items, _ := list.([]any)
+	var objs []map[string]any
+	for _, item := range items {
+		if obj, ok := item.(map[string]any); ok {
+			objs = append(objs, obj)
+		}
+	}
+	return objs
+}
+
+// SaveGame is everything needed to restore a game in progress into a freshly
+// set up level. Level entities (items, doors, walls, ...) are listed in the
+// order the level adds them, so a save can only be restored into the level it
+// was taken from.
+type SaveGame struct {
+	Version int
+	Level   string
+
+	Player      PlayerSave
+	Items       []bool // whether each item has been picked up
+	Doors       []DoorSave
+	Walls       []float32 // health of each destructible wall; 0 is destroyed
+	Secrets     []bool    // whether each secret has been found
+	Elevators   []ElevatorSave
+	Projectiles []ProjectileSave
+	Bombs       []BombSave `json:",omitempty"`
+	Enemies     []EnemySave
+	Waves       *WaveSave `json:",omitempty"` // survival mode only
+}
+
+// PlayerSave is the saved state of the player.
+type PlayerSave struct {
+	Position engo.Point // SpaceComponent.Position, including PlayerOffset
+	Rotation float32
+	Height   float32
+
+	Speed, RotSpeed     float32
+	Health, Stamina     float32
+	Armor               float32
+	NormalHeight, Floor float32
+	Exhausted, Jumping  bool
+	JumpVelocity        float32
+	Elevation, FallFrom float32
+	Velocity            engo.Point
+	DashTime            float32
+	DashCooldown        float32
+	MeleeSwing          float32
+	Loaded              int
+	ShotCooldown        float32
+	Keys                []Key
+	Bombs               int
+	// Weapon is the index of the weapon in hand, and WeaponsLoaded how
+	// many shots each weapon has loaded.
+	Weapon        int
+	WeaponsLoaded []int `json:",omitempty"`
+}
+
+// DoorSave is the saved state of a door.
+type DoorSave struct {
+	Open     bool
+	Height   float32
+	Passable bool
+}
+
+// ElevatorSave is the saved state of an elevator.
+type ElevatorSave struct {
+	Floor float32
+	Up    bool
+	Rest  float32
+}
+
+// ProjectileSave is a projectile in flight.
+type ProjectileSave struct {
+	Position engo.Point
+	Velocity engo.Point
+	Lifetime float32
+	Damage   float32
+	// Who fired it: saveOwnerPlayer, an enemy's number, or saveOwnerNone.
+	Owner   int
+	Faction Faction
+	Origin  engo.Point
+	// Height is how high above the base floor it is. Projectiles stuck in
+	// walls are loaded as stuck in the floor, so they stay after the wall
+	// is gone.
+	Height, Climb, Gravity float32
+	Stick                  float32
+	Stuck                  bool
+	// Weapon is the index of the player's weapon that fired it, if any,
+	// which it looks like.
+	Weapon int
+	// The bounces, pierces and splits it has left. The targets it has
+	// already pierced aren't saved, so after loading it may hit them again.
+	ProjectileBehaviour
+}
+
+// BombSave is a thrown bomb that hasn't gone off yet.
+type BombSave struct {
+	Position engo.Point
+	Height   float32
+	Velocity engo.Point
+	Climb    float32
+	Fuse     float32
+	// Who threw it, as for ProjectileSave.
+	Owner int
+}
+
+// EnemySave is the saved state of an enemy.
+type EnemySave struct {
+	Position engo.Point
+	Rotation float32
+	Health   float32
+	Speed    float32
+	// What its attacks do, which waves scale up.
+	AttackDamage, MeleeDamage float32
+
+	// How aware the enemy was of the player.
+	State     EnemyState
+	LastKnown engo.Point
+	Search    float32
+}
+
+// Save writes the game to path.
+func (g *SaveGame) Save(path string) error {
+	b, err := json.MarshalIndent(g, "", "\t")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, b, 0o644)
+}
+
+// LoadSave reads a save file written by Save, migrating it from older
+// versions of the format where possible.
+func LoadSave(path string) (*SaveGame, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var raw map[string]any
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return nil, fmt.Errorf("%s is not a save file: %w", path, err)
+	}
+	v, ok := raw["Version"].(float64)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a save file: no version", path)
+	}
+	version := int(v)
+	if version > SaveVersion {
+		return nil, fmt.Errorf("%s is from a newer version of the game (save version %d, this game reads up to %d)", path, version, SaveVersion)
+	}
+	for ; version < SaveVersion; version++ {
+		migrate, ok := saveMigrations[version]
+		if !ok {
+			return nil, fmt.Errorf("%s is from an old version of the game (save version %d) that can no longer be loaded", path, version)
+		}
+		if err := migrate(raw); err != nil {
+			return nil, fmt.Errorf("%s could not be upgraded from save version %d: %w", path, version, err)
+		}
+		raw["Version"] = version + 1
+	}
+
+	if b, err = json.Marshal(raw); err != nil {
+		return nil, err
+	}
+	g := &SaveGame{}
+	if err := json.Unmarshal(b, g); err != nil {
+		return nil, fmt.Errorf("%s is corrupt: %w", path, err)
+	}
+	return g, nil
+}
+
+type savePlayer struct {
+	*ecs.BasicEntity
+	*common.SpaceComponent
+	*ControlComponent
+	*ArcheryComponent
+	inventory *InventoryComponent    // nil when the player has no inventory
+	melee     *MeleeComponent        // nil when the player can't melee
+	lerp      *InterpolatedComponent // nil when the player isn't interpolated
+}
+
+type saveItem struct {
+	*ecs.BasicEntity
+	taken bool
+}
+
+type saveWall struct {
+	*ecs.BasicEntity
+	*DestructibleComponent
+}
+
+type saveDoor struct {
+	*ecs.BasicEntity
+	*DoorComponent
+	*WallMapComponent
+	*ViewWallComponent
+}
+
+type saveProjectile struct {
+	*ecs.BasicEntity
+	*common.SpaceComponent
+	*ProjectileComponent
+}
+
+type saveBomb struct {
+	*ecs.BasicEntity
+	*common.SpaceComponent
+	*BombComponent
+}
+
+type saveEnemy struct {
+	*ecs.BasicEntity
+	*common.SpaceComponent
+	*EnemyComponent
+}
+
+// SaveSystem quicksaves the game to Path when the "quicksave" control is
+// pressed, and reads it back when "quickload" is. Loading hands the save to
+// OnLoad, which is expected to set the level up afresh and Restore the save
+// into it; with no OnLoad, quickloading is disabled. The save itself is taken
+// in Tick, so it should be the last system in the tick order.
+//
+//	saveSystem := &systems.SaveSystem{Path: "quicksave.sav", Level: "Start Scene"}
+//	w.AddSystemInterface(saveSystem, []any{
+//		playerable, itemable, doorable, destructibleable, secretwallable,
+//		secretareaable, elevatorable, projectileable, bombable, enemyable,
+//	}, nil)
+type SaveSystem struct {
+	// Path is the quicksave file.
+	Path string
+	// Level identifies the level, so that saves are only loaded into the
+	// level they were taken in.
+	Level string
+	// OnLoad is called with a save that has been read back.
+	OnLoad func(*SaveGame)
+	// Controls holds the quicksave and quickload bindings. When nil only
+	// their keys are checked.
+	Controls *ControlsConfig
+
+	projectileSystem *ProjectileSystem
+	bombSystem       *BombSystem
+	enemySystem      *EnemySystem
+	waveSystem       *WaveSystem
+
+	player      *savePlayer
+	items       []*saveItem
+	doors       []saveDoor
+	walls       []saveWall
+	secrets     []*SecretComponent
+	elevators   []*ElevatorComponent
+	projectiles []saveProjectile
+	bombs       []saveBomb
+	enemies     []saveEnemy
+
+	saving bool
+}
+
+// SetProjectileSystem links this system to the ProjectileSystem so it can
+// respawn saved projectiles.
+func (s *SaveSystem) SetProjectileSystem(ps *ProjectileSystem) {
+	s.projectileSystem = ps
+}
+
+// SetBombSystem links this system to the BombSystem so it can respawn saved
+// bombs.
+func (s *SaveSystem) SetBombSystem(bs *BombSystem) {
+	s.bombSystem = bs
+}
+
+// SetEnemySystem links this system to the EnemySystem so it can respawn
+// saved enemies.
+func (s *SaveSystem) SetEnemySystem(es *EnemySystem) {
+	s.enemySystem = es
+}
+
+// SetWaveSystem links this system to the WaveSystem so that survival games
+// save how far they have got.
+func (s *SaveSystem) SetWaveSystem(ws *WaveSystem) {
+	s.waveSystem = ws
+}
+
+func (s *SaveSystem) AddByInterface(i ecs.Identifier) {
+	if o, ok := i.(ViewPlayerAble); ok {
+		s.player = &savePlayer{
+			BasicEntity:      o.GetBasicEntity(),
+			SpaceComponent:   o.GetSpaceComponent(),
+			ControlComponent: o.GetControlComponent(),
+			ArcheryComponent: o.GetArcheryComponent(),
+		}
+		if m, ok := i.(MeleeFace); ok {
+			s.player.melee = m.GetMeleeComponent()
+		}
+		if inv, ok := i.(InventoryFace); ok {
+			s.player.inventory = inv.GetInventoryComponent()
+		}
+		if l, ok := i.(InterpolatedFace); ok {
+			s.player.lerp = l.GetInterpolatedComponent()
+		}
+		return
+	}
+	if o, ok := i.(ProjectileAble); ok {
+		s.projectiles = append(s.projectiles, saveProjectile{o.GetBasicEntity(), o.GetSpaceComponent(), o.GetProjectileComponent()})
+		return
+	}
+	if o, ok := i.(BombAble); ok {
+		s.bombs = append(s.bombs, saveBomb{o.GetBasicEntity(), o.GetSpaceComponent(), o.GetBombComponent()})
+		return
+	}
+	if o, ok := i.(EnemyAble); ok {
+		s.enemies = append(s.enemies, saveEnemy{o.GetBasicEntity(), o.GetSpaceComponent(), o.GetEnemyComponent()})
+		return
+	}
+	if o, ok := i.(ItemAble); ok {
+		s.items = append(s.items, &saveItem{BasicEntity: o.GetBasicEntity()})
+		return
+	}
+
+	// The rest are level features, some of which are several at once (a
+	// secret door is a door, and may be destructible).
+	if o, ok := i.(DoorAble); ok {
+		s.doors = append(s.doors, saveDoor{o.GetBasicEntity(), o.GetDoorComponent(), o.GetWallMapComponent(), o.GetViewWallComponent()})
+	}
+	if o, ok := i.(DestructibleAble); ok {
+		s.walls = append(s.walls, saveWall{o.GetBasicEntity(), o.GetDestructibleComponent()})
+	}
+	if o, ok := i.(SecretFace); ok {
+		s.secrets = append(s.secrets, o.GetSecretComponent())
+	}
+	if o, ok := i.(ElevatorFace); ok {
+		s.elevators = append(s.elevators, o.GetElevatorComponent())
+	}
+}
+
+// Remove forgets the player, projectiles, bombs and enemies, and notes picked-up
+// items.
+// Destroyed walls stay listed, with no health left.
+func (s *SaveSystem) Remove(basic ecs.BasicEntity) {
+	if s.player != nil && s.player.ID() == basic.ID() {
+		s.player = nil
+		return
+	}
+	for i, p := range s.projectiles {
+		if p.ID() == basic.ID() {
+			s.projectiles = append(s.projectiles[:i], s.projectiles[i+1:]...)
+			return
+		}
+	}
+	for i, b := range s.bombs {
+		if b.ID() == basic.ID() {
+			s.bombs = append(s.bombs[:i], s.bombs[i+1:]...)
+			return
+		}
+	}
+	for i, e := range s.enemies {
+		if e.ID() == basic.ID() {
+			s.enemies = append(s.enemies[:i], s.enemies[i+1:]...)
+			return
+		}
+	}
+	for _, item := range s.items {
+		if item.ID() == basic.ID() {
+			item.taken = true
+			return
+		}
+	}
+}
+
+// Update watches for the quicksave and quickload buttons.
+func (s *SaveSystem) Update(dt float32) {
+	if s.Controls.JustPressed("quicksave") {
+		// Taken at the end of the next tick, when the world is in a
+		// simulated rather than an interpolated state.
+		s.saving = true
+	}
+	if s.Controls.JustPressed("quickload") {
+		s.quickload()
+	}
+}
+
+func (s *SaveSystem) Tick(dt float32) {
+	if !s.saving {
+		return
+	}
+	s.saving = false
+	if s.player == nil {
+		engo.Mailbox.Dispatch(HUDMessage{Text: "Can't save now"})
+		return
+	}
+	if err := s.Snapshot().Save(s.Path); err != nil {
+		println("Warning: could not save the game:", err.Error())
+		engo.Mailbox.Dispatch(HUDMessage{Text: "Save failed"})
+		return
+	}
+	engo.Mailbox.Dispatch(HUDMessage{Text: "Game saved"})
+}
+
+func (s *SaveSystem) quickload() {
+	if s.OnLoad == nil {
+		engo.Mailbox.Dispatch(HUDMessage{Text: "Can't load now"})
+		return
+	}
+	g, err := LoadSave(s.Path)
+	if err != nil {
+		println("Warning: could not load the game:", err.Error())
+		engo.Mailbox.Dispatch(HUDMessage{Text: "Load failed"})
+		return
+	}
+	s.OnLoad(g)
+}
+
+// Snapshot captures the current state of the game.
+func (s *SaveSystem) Snapshot() *SaveGame {
+	g := &SaveGame{Version: SaveVersion, Level: s.Level}
+	if p := s.player; p != nil {
+		g.Player = PlayerSave{
+			Position:     p.Position,
+			Rotation:     p.Rotation,
+			Height:       p.SpaceComponent.Height,
+			Speed:        p.Speed,
+			RotSpeed:     p.RotSpeed,
+			Health:       p.Health,
+			Stamina:      p.Stamina,
+			Armor:        p.Defense.Armor,
+			NormalHeight: p.NormalHeight,
+			Floor:        p.Floor,
+			Exhausted:    p.exhausted,
+			Jumping:      p.isJumping,
+			JumpVelocity: p.jumpVelocity,
+			Elevation:    p.elevation,
+			FallFrom:     p.fallFrom,
+			Velocity:     p.velocity,
+			DashTime:     p.dashTime,
+			DashCooldown: p.dashCooldown,
+			Loaded:       p.Ammo.Loaded,
+			ShotCooldown: p.ShotCooldown,
+			Weapon:       p.Weapon(),
+		}
+		for i, w := range p.Weapons {
+			if i == p.Weapon() {
+				w = p.Ammo
+			}
+			g.Player.WeaponsLoaded = append(g.Player.WeaponsLoaded, w.Loaded)
+		}
+		if p.melee != nil {
+			g.Player.MeleeSwing = p.melee.swing
+		}
+		if p.inventory != nil {
+			g.Player.Keys = append([]Key(nil), p.inventory.Keys()...)
+			g.Player.Bombs = p.inventory.Bombs()
+		}
+	}
+	for _, item := range s.items {
+		g.Items = append(g.Items, item.taken)
+	}
+	for _, d := range s.doors {
+		g.Doors = append(g.Doors, DoorSave{Open: d.open, Height: d.ViewWallComponent.Height, Passable: d.Passable})
+	}
+	for _, w := range s.walls {
+		g.Walls = append(g.Walls, w.Health)
+	}
+	for _, c := range s.secrets {
+		g.Secrets = append(g.Secrets, c.found)
+	}
+	for _, e := range s.elevators {
+		g.Elevators = append(g.Elevators, ElevatorSave{Floor: e.floor, Up: e.up, Rest: e.rest})
+	}
+	// Projectiles' and bombs' owners are saved as who they are rather than
+	// by ID, as IDs change when the game is loaded.
+	owners := map[uint64]int{}
+	if s.player != nil {
+		owners[s.player.ID()] = saveOwnerPlayer
+	}
+	for i, e := range s.enemies {
+		owners[e.ID()] = i + 1
+	}
+	for _, p := range s.projectiles {
+		ps := ProjectileSave{
+			Position: p.Position,
+			Velocity: p.Velocity,
+			Lifetime: p.Lifetime,
+			Damage:   p.Damage,
+			Owner:    owners[p.Owner],
+			Faction:  p.Faction,
+			Origin:   p.Origin,
+			Height:   p.SpaceComponent.Height,
+			Climb:    p.Climb,
+			Gravity:  p.Gravity,
+			Stick:    p.Stick,
+			Stuck:    p.stuck,
+
+			ProjectileBehaviour: p.ProjectileBehaviour,
+		}
+		if s.player != nil {
+			for i, w := range s.player.Weapons {
+				if w.ProjectileTex == p.Tex {
+					ps.Weapon = i
+					break
+				}
+			}
+		}
+		g.Projectiles = append(g.Projectiles, ps)
+	}
+	for _, b := range s.bombs {
+		g.Bombs = append(g.Bombs, BombSave{
+			Position: b.Position,
+			Height:   b.Height,
+			Velocity: b.Velocity,
+			Climb:    b.Climb,
+			Fuse:     b.Fuse,
+			Owner:    owners[b.Owner],
+		})
+	}
+	for _, e := range s.enemies {
+		g.Enemies = append(g.Enemies, EnemySave{
+			Position: e.Position,
+			Rotation: e.Rotation,
+			Health:   e.Health,
+			Speed:    e.Speed,
+
+			AttackDamage: e.AttackDamage,
+			MeleeDamage:  e.MeleeDamage,
+
+			State:     e.state,
+			LastKnown: e.lastKnown,
+			Search:    e.search,
+		})
+	}
+	if s.waveSystem != nil {
+		g.Waves = s.waveSystem.save()
+	}
+	return g
+}
+
+// Restore puts a saved game into the freshly set up level. It fails without
+// changing anything if the save doesn't fit the level.
+func (s *SaveSystem) Restore(w *ecs.World, g *SaveGame) error {
+	switch {
+	case g.Level != s.Level:
+		return fmt.Errorf("save is for level %q, not %q", g.Level, s.Level)
+	case s.player == nil:
+		return errors.New("there is no player to restore")
+	case len(g.Items) != len(s.items) || len(g.Doors) != len(s.doors) ||
+		len(g.Walls) != len(s.walls) || len(g.Secrets) != len(s.secrets) ||
+		len(g.Elevators) != len(s.elevators):
+		return fmt.Errorf("save doesn't match the layout of level %q", s.Level)
+	}
+
+	p, sp := s.player, g.Player
+	p.Position = sp.Position
+	p.Rotation = sp.Rotation
+	p.SpaceComponent.Height = sp.Height
+	if p.lerp != nil {
+		p.lerp.snap()
+	}
+	p.Speed, p.RotSpeed = sp.Speed, sp.RotSpeed
+	p.Health, p.Stamina = sp.Health, sp.Stamina
+	p.Defense.Armor = sp.Armor
+	p.NormalHeight, p.Floor = sp.NormalHeight, sp.Floor
+	p.exhausted, p.isJumping, p.jumpVelocity = sp.Exhausted, sp.Jumping, sp.JumpVelocity
+	p.elevation, p.fallFrom = sp.Elevation, sp.FallFrom
+	p.velocity = sp.Velocity
+	p.dashTime, p.dashCooldown = sp.DashTime, sp.DashCooldown
+	if p.melee != nil {
+		p.melee.swing = sp.MeleeSwing
+	}
+	if sp.Weapon < len(p.Weapons) {
+		p.arm(sp.Weapon)
+	}
+	for i, n := range sp.WeaponsLoaded {
+		if i < len(p.Weapons) {
+			p.Weapons[i].Loaded = n
+		}
+	}
+	p.Ammo.Loaded = sp.Loaded
+	p.ShotCooldown = sp.ShotCooldown
+	if p.inventory != nil {
+		for _, k := range sp.Keys {
+			p.inventory.AddKey(k)
+		}
+		p.inventory.bombs = sp.Bombs
+	}
+
+	for i, d := range g.Doors {
+		door := s.doors[i]
+		door.open = d.Open
+		door.ViewWallComponent.Height = d.Height
+		door.Passable = d.Passable
+	}
+	for i, found := range g.Secrets {
+		s.secrets[i].found = found
+	}
+	for i, e := range g.Elevators {
+		s.elevators[i].floor, s.elevators[i].up, s.elevators[i].rest = e.Floor, e.Up, e.Rest
+	}
+
+	// Removing entities changes s.items and s.walls' entries, so collect
+	// them first.
+	var gone []ecs.BasicEntity
+	for i, taken := range g.Items {
+		if taken {
+			gone = append(gone, *s.items[i].BasicEntity)
+		}
+	}
+	for i, health := range g.Walls {
+		s.walls[i].Health = health
+		if health <= 0 {
+			gone = append(gone, *s.walls[i].BasicEntity)
+		}
+	}
+	if s.enemySystem != nil {
+		// The level's own enemies are replaced by the saved ones, which
+		// include any spawned since.
+		for _, e := range s.enemies {
+			gone = append(gone, *e.BasicEntity)
+		}
+	}
+	for _, basic := range gone {
+		w.RemoveEntity(basic)
+	}
+
+	// The new IDs of the saved projectiles' owners.
+	owners := map[int]uint64{saveOwnerPlayer: p.ID()}
+	if s.enemySystem != nil {
+		for i, e := range g.Enemies {
+			ec := s.enemySystem.Spawn
+			ec.Health, ec.Speed = e.Health, e.Speed
+			ec.AttackDamage, ec.MeleeDamage = e.AttackDamage, e.MeleeDamage
+			ec.state, ec.lastKnown, ec.search = e.State, e.LastKnown, e.Search
+			owners[i+1] = s.enemySystem.spawn(e.Position, e.Rotation, ec)
+		}
+	}
+
+	if s.projectileSystem != nil {
+		for _, proj := range g.Projectiles {
+			tex := p.Ammo.ProjectileTex
+			if proj.Weapon < len(p.Weapons) {
+				tex = p.Weapons[proj.Weapon].ProjectileTex
+			}
+			if proj.Faction == FactionEnemy {
+				tex = nil
+				if s.enemySystem != nil {
+					tex = s.enemySystem.Spawn.ShotTex
+				}
+			}
+			s.projectileSystem.spawn(proj.Position, proj.Height, ProjectileComponent{
+				Velocity: proj.Velocity,
+				Lifetime: proj.Lifetime,
+				Damage:   proj.Damage,
+				Tex:      tex,
+				Owner:    owners[proj.Owner],
+				Faction:  proj.Faction,
+				Origin:   proj.Origin,
+				Climb:    proj.Climb,
+				Gravity:  proj.Gravity,
+				Stick:    proj.Stick,
+				stuck:    proj.Stuck,
+
+				ProjectileBehaviour: proj.ProjectileBehaviour,
+			})
+		}
+	}
+
+	if s.bombSystem != nil {
+		for _, b := range g.Bombs {
+			s.bombSystem.spawn(b.Position, b.Height, BombComponent{
+				Velocity: b.Velocity,
+				Climb:    b.Climb,
+				Fuse:     b.Fuse,
+				Owner:    owners[b.Owner],
+			})
+		}
+	}
+
+	if s.waveSystem != nil && g.Waves != nil {
+		s.waveSystem.restore(g.Waves)
+	}
+	return nil
+}
@@ -0,0 +1,234 @@
+package systems
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/EngoEngine/engo"
+
+	"github.com/SkeleboyStudios/SkeleDoom/shaders"
+)
+
+func TestSaveRoundTrip(t *testing.T) {
+	g := &SaveGame{
+		Version: SaveVersion,
+		Level:   "Start Scene",
+		Player: PlayerSave{
+			Position:      engo.Point{X: 10, Y: -20},
+			Rotation:      45,
+			Height:        18,
+			Speed:         150,
+			RotSpeed:      25,
+			Health:        80,
+			Stamina:       40,
+			Armor:         25,
+			NormalHeight:  20,
+			Floor:         2,
+			Jumping:       true,
+			JumpVelocity:  3,
+			Velocity:      engo.Point{X: 1},
+			Loaded:        5,
+			ShotCooldown:  0.5,
+			Keys:          []Key{KeyRed},
+			Bombs:         2,
+			Weapon:        1,
+			WeaponsLoaded: []int{5, 1},
+		},
+		Items:     []bool{true, false},
+		Doors:     []DoorSave{{Open: true, Height: 4, Passable: true}},
+		Walls:     []float32{75, 0},
+		Secrets:   []bool{false},
+		Elevators: []ElevatorSave{{Floor: 12, Up: true, Rest: 1}},
+		Projectiles: []ProjectileSave{
+			{Position: engo.Point{X: 1, Y: 2}, Velocity: engo.Point{Y: -5}, Lifetime: 2, Damage: 10, Owner: saveOwnerPlayer, Height: 38, Climb: 20, Gravity: 120, Stick: 4, Weapon: 1,
+				ProjectileBehaviour: ProjectileBehaviour{Bounces: 2, Restitution: 0.5, Pierce: 1, Homing: 90, HomingCone: 30, HomingRange: 200, Split: 3, SplitSpread: 20}},
+			{Position: engo.Point{X: 3, Y: 4}, Velocity: engo.Point{X: 5}, Lifetime: 1, Damage: 8, Owner: 1, Faction: FactionEnemy, Origin: engo.Point{X: 30, Y: 40}},
+		},
+		Bombs: []BombSave{{Position: engo.Point{X: 5, Y: 6}, Height: 3, Velocity: engo.Point{X: 40}, Climb: 10, Fuse: 1.5, Owner: saveOwnerPlayer}},
+		Enemies: []EnemySave{{
+			Position:     engo.Point{X: 30, Y: 40},
+			Rotation:     180,
+			Health:       20,
+			Speed:        60,
+			AttackDamage: 12,
+			MeleeDamage:  18,
+			State:        EnemyAlert,
+			LastKnown:    engo.Point{X: 10, Y: -20},
+			Search:       2,
+		}},
+		Waves: &WaveSave{Wave: 3, Phase: 1, Timer: 2.5, Left: 4, Next: 1, Score: 900, Kills: 12},
+	}
+	path := filepath.Join(t.TempDir(), "test.sav")
+	if err := g.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	got, err := LoadSave(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, g) {
+		t.Errorf("loaded %+v, want %+v", got, g)
+	}
+}
+
+// loadRaw writes a save file with the given contents and loads it.
+func loadRaw(t *testing.T, contents string) (*SaveGame, error) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "test.sav")
+	if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return LoadSave(path)
+}
+
+func TestLoadSaveErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		contents string
+		want     string
+	}{
+		{"not json", "SKDM", "is not a save file"},
+		{"no version", `{"Level": "Start Scene"}`, "no version"},
+		{"newer", `{"Version": 1000}`, "newer version"},
+		{"old", `{"Version": 0}`, "can no longer be loaded"},
+		{"corrupt", fmt.Sprintf(`{"Version": %d, "Items": "all of them"}`, SaveVersion), "is corrupt"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := loadRaw(t, tt.contents)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("got error %v, want one containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+// saveV1 is a save file written by the first version of the format.
+const saveV1 = `{
+	"Version": 1,
+	"Level": "Start Scene",
+	"Player": {
+		"Position": {"X": 40, "Y": 60},
+		"Rotation": 90,
+		"Height": 20,
+		"Speed": 150,
+		"RotSpeed": 25,
+		"Health": 90,
+		"Stamina": 100,
+		"NormalHeight": 20,
+		"Loaded": 7,
+		"Keys": ["red"]
+	},
+	"Items": [true, false, false],
+	"Doors": [{"Open": false, "Height": 0, "Passable": false}],
+	"Walls": [75],
+	"Secrets": [false],
+	"Elevators": [{"Floor": 0, "Up": true, "Rest": 2}],
+	"Projectiles": [{"Position": {"X": 50, "Y": 70}, "Velocity": {"X": 0, "Y": -6}, "Lifetime": 3, "Damage": 25}]
+}`
+
+func TestSaveMigration(t *testing.T) {
+	got, err := loadRaw(t, saveV1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &SaveGame{
+		Version: SaveVersion,
+		Level:   "Start Scene",
+		Player: PlayerSave{
+			Position:     engo.Point{X: 40, Y: 60},
+			Rotation:     90,
+			Height:       20,
+			Speed:        150,
+			RotSpeed:     25,
+			Health:       90,
+			Stamina:      100,
+			Armor:        0, // there was no armor
+			NormalHeight: 20,
+			Loaded:       7,
+			Keys:         []Key{KeyRed},
+		},
+		Items:     []bool{true, false, false},
+		Doors:     []DoorSave{{}},
+		Walls:     []float32{75},
+		Secrets:   []bool{false},
+		Elevators: []ElevatorSave{{Up: true, Rest: 2}},
+		Projectiles: []ProjectileSave{{
+			Position: engo.Point{X: 50, Y: 70},
+			Velocity: engo.Point{Y: -6},
+			Lifetime: 3,
+			Damage:   25,
+			// Only the player used to shoot.
+			Owner:   saveOwnerPlayer,
+			Faction: FactionPlayer,
+			Origin:  engo.Point{X: 50, Y: 70},
+			// Fired flat from the player's eye.
+			Height: defaultWallHeight - 20,
+		}},
+		Bombs:   []BombSave{},
+		Enemies: []EnemySave{},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("loaded %+v, want %+v", got, want)
+	}
+}
+
+// saveV2 is a version 2 save file, the first with enemies.
+const saveV2 = `{
+	"Version": 2,
+	"Level": "Start Scene",
+	"Player": {"Position": {"X": 40, "Y": 60}, "Health": 100},
+	"Enemies": [{"Position": {"X": 150, "Y": 20}, "Rotation": 45, "Health": 30, "Speed": 60}]
+}`
+
+func TestSaveMigrationEnemies(t *testing.T) {
+	got, err := loadRaw(t, saveV2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	po := shaders.PlayerOffset
+	want := []EnemySave{{
+		Position: engo.Point{X: 150, Y: 20},
+		Rotation: 45,
+		Health:   30,
+		Speed:    60,
+		// Enemies used to all do the same damage, and always chase the
+		// player.
+		AttackDamage: enemyAttackDamage,
+		MeleeDamage:  enemyMeleeDamage,
+		State:        EnemyHunting,
+		LastKnown:    engo.Point{X: 40 - po.X, Y: 60 - po.Y},
+	}}
+	if !reflect.DeepEqual(got.Enemies, want) {
+		t.Errorf("loaded enemies %+v, want %+v", got.Enemies, want)
+	}
+}
+
+// saveV7 is a version 7 save file, from before projectiles had a height.
+const saveV7 = `{
+	"Version": 7,
+	"Level": "Start Scene",
+	"Player": {"Position": {"X": 40, "Y": 60}, "Health": 100, "NormalHeight": 25},
+	"Projectiles": [
+		{"Position": {"X": 50, "Y": 70}, "Faction": 0, "Owner": -1},
+		{"Position": {"X": 60, "Y": 80}, "Faction": 1, "Owner": 1}
+	],
+	"Enemies": [{"Position": {"X": 150, "Y": 20}, "Health": 30}]
+}`
+
+func TestSaveMigrationProjectileHeight(t *testing.T) {
+	got, err := loadRaw(t, saveV7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []float32{defaultWallHeight - 25, enemyMuzzleHeight}
+	for i, p := range got.Projectiles {
+		if p.Height != want[i] {
+			t.Errorf("projectile %d loaded at height %v, want %v", i, p.Height, want[i])
+		}
+	}
+}
@@ -0,0 +1,215 @@
+package systems
+
+import (
+	"fmt"
+	"image/color"
+
+	"github.com/EngoEngine/ecs"
+	"github.com/EngoEngine/engo"
+	"github.com/EngoEngine/engo/common"
+
+	"github.com/SkeleboyStudios/SkeleDoom/shaders"
+)
+
+const (
+	// Secret counter HUD position (screen coordinates).
+	secretCounterX float32 = 10
+	secretCounterY float32 = 10
+)
+
+// SecretComponent marks an entity as one of the level's secrets. On a door
+// it is found when the door is opened; on an area it is found when the player
+// first walks into it. Secret areas stay off the minimap until found.
+type SecretComponent struct {
+	found bool
+}
+
+func (c *SecretComponent) GetSecretComponent() *SecretComponent { return c }
+
+// Found reports whether the player has discovered this secret.
+func (c *SecretComponent) Found() bool { return c.found }
+
+// SecretFace is satisfied by anything that embeds *SecretComponent.
+type SecretFace interface {
+	GetSecretComponent() *SecretComponent
+}
+
+// SecretWallAble is a secret door.
+type SecretWallAble interface {
+	common.BasicFace
+	SecretFace
+	DoorFace
+}
+
+// SecretAreaAble is a rectangular secret area. Like lava zones, its position
+// and size are given in wall world-space in the SpaceComponent, and overlap
+// with the player is detected by engo's CollisionSystem.
+type SecretAreaAble interface {
+	common.BasicFace
+	common.SpaceFace
+	common.CollisionFace
+	SecretFace
+}
+
+type secretWallEntity struct {
+	*ecs.BasicEntity
+	*SecretComponent
+	*DoorComponent
+}
+
+type secretAreaEntity struct {
+	*ecs.BasicEntity
+	*SecretComponent
+	*common.CollisionComponent
+	mapRect sprite // minimap rectangle, hidden until found
+}
+
+// SecretSystem tracks the level's secret doors and areas, announces each
+// discovery with a HUDMessage, and shows a found/total counter on the HUD.
+type SecretSystem struct {
+	w *ecs.World
+
+	walls []*secretWallEntity
+	areas []*secretAreaEntity
+
+	font    *common.Font
+	counter sprite
+	shown   string // text currently on the counter
+}
+
+func (s *SecretSystem) New(w *ecs.World) {
+	s.w = w
+	s.font = newHUDFont(12, color.RGBA{0xFF, 0xD7, 0x00, 0xFF})
+
+	s.counter = sprite{BasicEntity: ecs.NewBasic()}
+	s.counter.Position = engo.Point{X: secretCounterX, Y: secretCounterY}
+	s.counter.RenderComponent = common.RenderComponent{
+		Drawable:    common.Text{Font: s.font},
+		StartZIndex: 60,
+		Hidden:      true,
+	}
+	s.counter.SetShader(common.HUDShader)
+	if s.font != nil {
+		w.AddEntity(&s.counter)
+	}
+}
+
+func (s *SecretSystem) AddByInterface(i ecs.Identifier) {
+	if o, ok := i.(SecretWallAble); ok {
+		s.walls = append(s.walls, &secretWallEntity{
+			BasicEntity:     o.GetBasicEntity(),
+			SecretComponent: o.GetSecretComponent(),
+			DoorComponent:   o.GetDoorComponent(),
+		})
+		return
+	}
+
+	o, ok := i.(SecretAreaAble)
+	if !ok {
+		return
+	}
+
+	// Same wall-space to collision-space shift as LavaSystem.
+	space := o.GetSpaceComponent()
+	space.Position.X += MapWallOffsetX
+	space.Position.Y += MapWallOffsetY
+
+	// CollisionGroupInterest is in the player's Group but not in
+	// CollisionSystem.Solids, so overlap is reported without pushing back.
+	collision := o.GetCollisionComponent()
+	collision.Main = CollisionGroupInterest
+	collision.Group = 0
+
+	area := &secretAreaEntity{
+		BasicEntity:        o.GetBasicEntity(),
+		SecretComponent:    o.GetSecretComponent(),
+		CollisionComponent: collision,
+	}
+	area.mapRect = sprite{BasicEntity: ecs.NewBasic()}
+	area.mapRect.SpaceComponent = common.SpaceComponent{
+		Position: space.Position,
+		Width:    space.Width,
+		Height:   space.Height,
+	}
+	area.mapRect.RenderComponent = common.RenderComponent{
+		Drawable:    common.Rectangle{},
+		Color:       color.RGBA{0xFF, 0xD7, 0x00, 0x88},
+		StartZIndex: 3,
+		Hidden:      !area.found,
+	}
+	area.mapRect.SetShader(shaders.MinimapShader)
+	s.w.AddEntity(&area.mapRect)
+
+	s.areas = append(s.areas, area)
+}
+
+func (s *SecretSystem) Remove(basic ecs.BasicEntity) {
+	for i, wall := range s.walls {
+		if wall.ID() == basic.ID() {
+			s.walls = append(s.walls[:i], s.walls[i+1:]...)
+			return
+		}
+	}
+	for i, area := range s.areas {
+		if area.ID() == basic.ID() {
+			despawn(s.w, area.mapRect.BasicEntity, &area.mapRect.RenderComponent)
+			s.areas = append(s.areas[:i], s.areas[i+1:]...)
+			return
+		}
+	}
+}
+
+// Found returns how many of the level's secrets have been discovered.
+func (s *SecretSystem) Found() int {
+	n := 0
+	for _, wall := range s.walls {
+		if wall.found {
+			n++
+		}
+	}
+	for _, area := range s.areas {
+		if area.found {
+			n++
+		}
+	}
+	return n
+}
+
+// Total returns the number of secrets in the level.
+func (s *SecretSystem) Total() int {
+	return len(s.walls) + len(s.areas)
+}
+
+// Update does nothing; secrets are discovered in Tick.
+func (s *SecretSystem) Update(dt float32) {}
+
+func (s *SecretSystem) Tick(dt float32) {
+	for _, wall := range s.walls {
+		if !wall.found && wall.Opening() {
+			s.discover(wall.SecretComponent)
+		}
+	}
+	for _, area := range s.areas {
+		if !area.found && area.Collides != 0 {
+			s.discover(area.SecretComponent)
+		}
+		// Also catches areas found in a loaded game.
+		area.mapRect.Hidden = !area.found
+	}
+
+	if s.font == nil || s.Total() == 0 {
+		s.counter.Hidden = true
+		return
+	}
+	text := fmt.Sprintf("Secrets %d/%d", s.Found(), s.Total())
+	if text != s.shown {
+		s.counter.Drawable = common.Text{Font: s.font, Text: text}
+		s.shown = text
+	}
+	s.counter.Hidden = false
+}
+
+func (s *SecretSystem) discover(c *SecretComponent) {
+	c.found = true
+	engo.Mailbox.Dispatch(HUDMessage{Text: "A secret is revealed!"})
+}
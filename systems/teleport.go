@@ -0,0 +1,356 @@
+package systems
+
+import (
+	"image/color"
+
+	"github.com/EngoEngine/ecs"
+	"github.com/EngoEngine/engo"
+	"github.com/EngoEngine/engo/common"
+	"github.com/EngoEngine/engo/math"
+
+	"github.com/SkeleboyStudios/SkeleDoom/shaders"
+)
+
+const (
+	// teleportFadeTime is how long the arrival flash takes to fade (seconds).
+	teleportFadeTime float32 = 0.6
+)
+
+// MarkerComponent names a point in the level, such as a teleport destination
+// or an enemy spawn point. The point itself is the entity's SpaceComponent:
+// Position in wall world-space, and Rotation as the facing angle in degrees
+// (same convention as the player's).
+type MarkerComponent struct {
+	Name string
+}
+
+func (c *MarkerComponent) GetMarkerComponent() *MarkerComponent { return c }
+
+// MarkerFace is satisfied by anything that embeds *MarkerComponent.
+type MarkerFace interface {
+	GetMarkerComponent() *MarkerComponent
+}
+
+// MarkerAble is the interface AddByInterface uses to detect markers.
+type MarkerAble interface {
+	common.BasicFace
+	common.SpaceFace
+	MarkerFace
+}
+
+type markerEntity struct {
+	*ecs.BasicEntity
+	*common.SpaceComponent
+	*MarkerComponent
+}
+
+// TeleporterComponent turns a rectangular area into a teleporter pad. Like
+// lava zones, the pad's position and size are given in wall world-space in
+// the entity's SpaceComponent.
+type TeleporterComponent struct {
+	// Destination is the Name of the marker the pad sends things to.
+	Destination string
+	// Projectiles makes the pad teleport projectiles as well as the player.
+	Projectiles bool
+	// Enemies makes the pad teleport enemies as well as the player.
+	Enemies bool
+}
+
+func (c *TeleporterComponent) GetTeleporterComponent() *TeleporterComponent { return c }
+
+// TeleporterFace is satisfied by anything that embeds *TeleporterComponent.
+type TeleporterFace interface {
+	GetTeleporterComponent() *TeleporterComponent
+}
+
+// TeleporterAble is the interface AddByInterface uses to detect pads.
+type TeleporterAble interface {
+	common.BasicFace
+	common.SpaceFace
+	TeleporterFace
+}
+
+type teleporterEntity struct {
+	*ecs.BasicEntity
+	*common.SpaceComponent
+	*TeleporterComponent
+	playerInside bool // the pad only fires again once the player steps off
+	mapRect      sprite
+}
+
+type teleportProjectileEntity struct {
+	*ecs.BasicEntity
+	*common.SpaceComponent
+	*ProjectileComponent
+	lerp *InterpolatedComponent // nil if it isn't interpolated
+}
+
+type teleportEnemyEntity struct {
+	*ecs.BasicEntity
+	*common.SpaceComponent
+	*EnemyComponent
+	*InterpolatedComponent
+	onPad *teleporterEntity // the pad it is standing on, if any
+}
+
+// TeleportSystem moves the player to a named marker on ActionTeleport (whose
+// Target is the marker's Name), and moves the player and optionally
+// projectiles and enemies that enter a teleporter pad to the pad's
+// Destination. Each player teleport is followed by a brief white flash that
+// fades out through grey fog.
+type TeleportSystem struct {
+	w *ecs.World
+
+	playerID    uint64
+	player      *common.SpaceComponent
+	playerLerp  *InterpolatedComponent // nil if the player isn't interpolated
+	markers     []markerEntity
+	pads        []*teleporterEntity
+	projectiles []teleportProjectileEntity
+	enemies     []*teleportEnemyEntity
+
+	flash     sprite
+	flashTime float32 // remaining fade time
+}
+
+func (s *TeleportSystem) New(w *ecs.World) {
+	s.w = w
+
+	s.flash = sprite{BasicEntity: ecs.NewBasic()}
+	s.flash.SpaceComponent = common.SpaceComponent{
+		Width:  engo.GameWidth(),
+		Height: engo.GameHeight(),
+	}
+	s.flash.RenderComponent = common.RenderComponent{
+		Drawable:    common.Rectangle{},
+		Color:       color.RGBA{},
+		StartZIndex: 55, // above the lava vignette, below HUD text
+	}
+	s.flash.SetShader(common.LegacyHUDShader)
+	w.AddEntity(&s.flash)
+
+	engo.Mailbox.Listen(ActionMessageType, func(msg engo.Message) {
+		m, ok := msg.(ActionMessage)
+		if !ok || m.Kind != ActionTeleport || s.player == nil {
+			return
+		}
+		if dest := s.marker(m.Target); dest != nil {
+			s.teleport(dest)
+		}
+	})
+}
+
+func (s *TeleportSystem) AddByInterface(i ecs.Identifier) {
+	if o, ok := i.(ViewPlayerAble); ok {
+		s.playerID = o.GetBasicEntity().ID()
+		s.player = o.GetSpaceComponent()
+		s.playerLerp = nil
+		if l, ok := i.(InterpolatedFace); ok {
+			s.playerLerp = l.GetInterpolatedComponent()
+		}
+		return
+	}
+	if o, ok := i.(MarkerAble); ok {
+		s.markers = append(s.markers, markerEntity{o.GetBasicEntity(), o.GetSpaceComponent(), o.GetMarkerComponent()})
+		return
+	}
+	if o, ok := i.(ProjectileAble); ok {
+		p := teleportProjectileEntity{o.GetBasicEntity(), o.GetSpaceComponent(), o.GetProjectileComponent(), nil}
+		if l, ok := i.(InterpolatedFace); ok {
+			p.lerp = l.GetInterpolatedComponent()
+		}
+		s.projectiles = append(s.projectiles, p)
+		return
+	}
+	if o, ok := i.(EnemyAble); ok {
+		s.enemies = append(s.enemies, &teleportEnemyEntity{
+			BasicEntity:           o.GetBasicEntity(),
+			SpaceComponent:        o.GetSpaceComponent(),
+			EnemyComponent:        o.GetEnemyComponent(),
+			InterpolatedComponent: o.GetInterpolatedComponent(),
+		})
+		return
+	}
+	o, ok := i.(TeleporterAble)
+	if !ok {
+		return
+	}
+	pad := &teleporterEntity{
+		BasicEntity:         o.GetBasicEntity(),
+		SpaceComponent:      o.GetSpaceComponent(),
+		TeleporterComponent: o.GetTeleporterComponent(),
+	}
+	pad.mapRect = sprite{BasicEntity: ecs.NewBasic()}
+	pad.mapRect.SpaceComponent = common.SpaceComponent{
+		Position: engo.Point{
+			X: pad.Position.X + MapWallOffsetX,
+			Y: pad.Position.Y + MapWallOffsetY,
+		},
+		Width:  pad.Width,
+		Height: pad.Height,
+	}
+	pad.mapRect.RenderComponent = common.RenderComponent{
+		Drawable:    common.Rectangle{},
+		Color:       color.RGBA{0x99, 0x33, 0xFF, 0xAA},
+		StartZIndex: 3,
+	}
+	pad.mapRect.SetShader(shaders.MinimapShader)
+	s.w.AddEntity(&pad.mapRect)
+	s.pads = append(s.pads, pad)
+}
+
+func (s *TeleportSystem) Remove(basic ecs.BasicEntity) {
+	if s.player != nil && s.playerID == basic.ID() {
+		s.player = nil
+		return
+	}
+	for i, m := range s.markers {
+		if m.ID() == basic.ID() {
+			s.markers = append(s.markers[:i], s.markers[i+1:]...)
+			return
+		}
+	}
+	for i, p := range s.projectiles {
+		if p.ID() == basic.ID() {
+			s.projectiles = append(s.projectiles[:i], s.projectiles[i+1:]...)
+			return
+		}
+	}
+	for i, e := range s.enemies {
+		if e.ID() == basic.ID() {
+			s.enemies = append(s.enemies[:i], s.enemies[i+1:]...)
+			return
+		}
+	}
+	for i, pad := range s.pads {
+		if pad.ID() == basic.ID() {
+			despawn(s.w, pad.mapRect.BasicEntity, &pad.mapRect.RenderComponent)
+			s.pads = append(s.pads[:i], s.pads[i+1:]...)
+			return
+		}
+	}
+}
+
+// Update does nothing; pads and the arrival flash are handled in Tick.
+func (s *TeleportSystem) Update(dt float32) {}
+
+func (s *TeleportSystem) Tick(dt float32) {
+	for _, pad := range s.pads {
+		dest := s.marker(pad.Destination)
+		if dest == nil {
+			continue
+		}
+
+		if s.player != nil {
+			po := shaders.PlayerOffset
+			inside := pad.contains(engo.Point{X: s.player.Position.X - po.X, Y: s.player.Position.Y - po.Y})
+			if inside && !pad.playerInside {
+				s.teleport(dest)
+				// The destination may itself be on a pad; don't bounce
+				// straight back off it.
+				for _, other := range s.pads {
+					other.playerInside = other.contains(dest.Position)
+				}
+				continue
+			}
+			pad.playerInside = inside
+		}
+
+		if !pad.Projectiles {
+			continue
+		}
+		for _, p := range s.projectiles {
+			if p.Stuck() || !pad.contains(p.Position) {
+				continue
+			}
+			// Keep the projectile's speed but send it off in the
+			// marker's facing direction.
+			speed := math.Sqrt(p.Velocity.X*p.Velocity.X + p.Velocity.Y*p.Velocity.Y)
+			sin, cos := math.Sincos(dest.Rotation * math.Pi / 180)
+			p.Position = dest.Position
+			p.Velocity = engo.Point{X: speed * sin, Y: -speed * cos}
+			if p.lerp != nil {
+				p.lerp.snap()
+			}
+		}
+	}
+
+	// Enemies only teleport once they step onto a pad, so that they don't
+	// bounce straight back off one at the destination.
+	for _, e := range s.enemies {
+		pad := s.padAt(e.Position)
+		if pad != nil && pad != e.onPad && pad.Enemies {
+			if dest := s.marker(pad.Destination); dest != nil {
+				s.teleportEnemy(e, dest)
+				pad = s.padAt(dest.Position)
+			}
+		}
+		e.onPad = pad
+	}
+
+	// ── Arrival flash ─────────────────────────────────────────────────────
+	if s.flashTime <= 0 {
+		return
+	}
+	s.flashTime -= dt
+	if s.flashTime < 0 {
+		s.flashTime = 0
+	}
+	// t runs from 1 (just arrived: bright white) to 0 (clear), passing
+	// through a translucent grey fog on the way.
+	t := s.flashTime / teleportFadeTime
+	shade := uint8(0x99 + float32(0xFF-0x99)*t)
+	s.flash.Color = color.RGBA{shade, shade, shade, uint8(0xFF * t)}
+}
+
+// contains reports whether p, in wall world-space, is on the pad.
+func (pad *teleporterEntity) contains(p engo.Point) bool {
+	return p.X >= pad.Position.X && p.X <= pad.Position.X+pad.Width &&
+		p.Y >= pad.Position.Y && p.Y <= pad.Position.Y+pad.Height
+}
+
+// padAt returns the pad p, in wall world-space, is on, or nil when there is
+// none.
+func (s *TeleportSystem) padAt(p engo.Point) *teleporterEntity {
+	for _, pad := range s.pads {
+		if pad.contains(p) {
+			return pad
+		}
+	}
+	return nil
+}
+
+// marker returns the marker called name, or nil when there is none.
+func (s *TeleportSystem) marker(name string) *markerEntity {
+	for i := range s.markers {
+		if s.markers[i].Name == name {
+			return &s.markers[i]
+		}
+	}
+	return nil
+}
+
+// teleport places the player on dest, facing dest's Rotation, and starts the
+// arrival flash. The player's SpaceComponent is updated in place rather than
+// replaced, so the ViewShader's player pointer, MapSystem's camera tracking
+// and the CollisionSystem all follow on the same frame, and they are drawn
+// there straight away rather than sliding over from the pad.
+func (s *TeleportSystem) teleport(dest *markerEntity) {
+	po := shaders.PlayerOffset
+	s.player.Position = engo.Point{X: dest.Position.X + po.X, Y: dest.Position.Y + po.Y}
+	s.player.Rotation = dest.Rotation
+	if s.playerLerp != nil {
+		s.playerLerp.snap()
+	}
+	s.flashTime = teleportFadeTime
+	s.flash.Color = color.RGBA{0xFF, 0xFF, 0xFF, 0xFF}
+}
+
+// teleportEnemy places e on dest, facing dest's Rotation. It forgets the path
+// it was walking, and any knockback, and plans a new one from there.
+func (s *TeleportSystem) teleportEnemy(e *teleportEnemyEntity, dest *markerEntity) {
+	e.Position = dest.Position
+	e.Rotation = dest.Rotation
+	e.path, e.repath, e.push = nil, 0, engo.Point{}
+	e.snap()
+}
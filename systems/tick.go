@@ -0,0 +1,218 @@
+package systems
+
+import (
+	"github.com/EngoEngine/ecs"
+	"github.com/EngoEngine/engo"
+	"github.com/EngoEngine/engo/common"
+)
+
+const (
+	// TickRate is the number of simulation ticks per second.
+	TickRate = 60
+	// TickDt is the length of one simulation tick, in seconds. Every Ticker
+	// is always stepped by exactly this much.
+	TickDt float32 = 1 / float32(TickRate)
+
+	// maxTicksPerFrame caps how many ticks a single rendered frame may run,
+	// so that a long stall (loading, dragging the window) doesn't snowball
+	// into ever longer frames. Time beyond the cap is dropped.
+	maxTicksPerFrame = 8
+
+	// tickSystemPriority makes the world update TickSystem before any other
+	// system but InputSystem, so per-frame work always sees this frame's
+	// simulation.
+	tickSystemPriority = 100
+)
+
+// Ticker is implemented by systems whose gameplay runs at the fixed TickRate
+// rather than once per rendered frame. Tick does the simulation; the
+// system's Update is still called by the world every frame, for work that
+// belongs to rendering (input sampling, positioning billboards, camera).
+type Ticker interface {
+	Tick(dt float32)
+}
+
+// pose is the part of a SpaceComponent that is interpolated for rendering.
+type pose struct {
+	Position engo.Point
+	Rotation float32
+	Height   float32
+}
+
+func poseOf(space *common.SpaceComponent) pose {
+	return pose{space.Position, space.Rotation, space.Height}
+}
+
+func (p pose) apply(space *common.SpaceComponent) {
+	space.Position = p.Position
+	space.Rotation = p.Rotation
+	space.Height = p.Height
+}
+
+// lerp returns the pose a fraction t of the way from p to q.
+func (p pose) lerp(q pose, t float32) pose {
+	return pose{
+		Position: engo.Point{
+			X: p.Position.X + (q.Position.X-p.Position.X)*t,
+			Y: p.Position.Y + (q.Position.Y-p.Position.Y)*t,
+		},
+		Rotation: p.Rotation + (q.Rotation-p.Rotation)*t,
+		Height:   p.Height + (q.Height-p.Height)*t,
+	}
+}
+
+// InterpolatedComponent marks an entity that moves during ticks and should be
+// drawn smoothly in between them. Between frames its SpaceComponent holds a
+// blend of the last two simulated poses; TickSystem puts the simulated pose
+// back before each tick, so gameplay code never sees the blend.
+type InterpolatedComponent struct {
+	prev, curr pose
+	tracking   bool
+}
+
+func (c *InterpolatedComponent) GetInterpolatedComponent() *InterpolatedComponent { return c }
+
+// snap draws the entity where it is from the next frame on, instead of
+// blending it in from where it was, for when a tick moves it in one jump
+// (e.g. a teleport).
+func (c *InterpolatedComponent) snap() { c.tracking = false }
+
+// InterpolatedFace is satisfied by anything that embeds *InterpolatedComponent.
+type InterpolatedFace interface {
+	GetInterpolatedComponent() *InterpolatedComponent
+}
+
+// InterpolatedAble is the interface AddByInterface uses to detect entities
+// to interpolate.
+type InterpolatedAble interface {
+	common.BasicFace
+	common.SpaceFace
+	InterpolatedFace
+}
+
+type interpolatedEntity struct {
+	*ecs.BasicEntity
+	*common.SpaceComponent
+	*InterpolatedComponent
+}
+
+// TickSystem runs the gameplay systems added to it at a fixed TickRate, so
+// that movement, physics and damage come out the same at any frame rate. It
+// accumulates frame time, runs as many whole ticks as fit, and then blends
+// the poses of InterpolatedAble entities by the leftover fraction of a tick.
+//
+//	tick := &systems.TickSystem{}
+//	var interpolatedable *systems.InterpolatedAble
+//	w.AddSystemInterface(tick, interpolatedable, nil)
+//	// ... add the gameplay systems to the world, then, in tick order:
+//	tick.Add(controlSystem, collisionSystem)
+type TickSystem struct {
+	// Speed scales how fast simulated time passes relative to real time,
+	// e.g. 4 to fast-forward a demo. Zero means 1. The cap on ticks per
+	// frame is scaled with it.
+	Speed float32
+	// Paused stops the simulation, e.g. while a menu is open. Rendering
+	// carries on with the last blended poses.
+	Paused bool
+
+	tickers  []Ticker
+	entities []interpolatedEntity
+
+	acc   float32 // frame time not yet simulated
+	alpha float32 // acc as a fraction of a tick
+	ticks uint64
+}
+
+// Priority runs TickSystem before every other system but InputSystem.
+func (s *TickSystem) Priority() int { return tickSystemPriority }
+
+// Add appends systems to the tick order. Each tick steps them in the order
+// they were added.
+func (s *TickSystem) Add(tickers ...Ticker) {
+	s.tickers = append(s.tickers, tickers...)
+}
+
+// Ticks returns the number of ticks simulated so far.
+func (s *TickSystem) Ticks() uint64 { return s.ticks }
+
+// Alpha returns how far rendering is between the last two ticks, in [0, 1).
+func (s *TickSystem) Alpha() float32 { return s.alpha }
+
+func (s *TickSystem) AddByInterface(i ecs.Identifier) {
+	o, ok := i.(InterpolatedAble)
+	if !ok {
+		return
+	}
+	// The pose is captured on the next Update rather than now, as other
+	// systems may still adjust the entity when it is added (e.g. MapSystem
+	// moves the player to its spawn point).
+	c := o.GetInterpolatedComponent()
+	c.tracking = false
+	s.entities = append(s.entities, interpolatedEntity{o.GetBasicEntity(), o.GetSpaceComponent(), c})
+}
+
+func (s *TickSystem) Remove(basic ecs.BasicEntity) {
+	for i, e := range s.entities {
+		if e.ID() == basic.ID() {
+			s.entities = append(s.entities[:i], s.entities[i+1:]...)
+			return
+		}
+	}
+}
+
+func (s *TickSystem) Update(dt float32) {
+	// Put the simulated poses back in place of last frame's blend.
+	for _, e := range s.entities {
+		if e.tracking {
+			e.curr.apply(e.SpaceComponent)
+		}
+	}
+
+	speed := s.Speed
+	if s.Paused {
+		speed = 0
+	} else if speed <= 0 {
+		speed = 1
+	}
+	limit := int(maxTicksPerFrame * speed)
+
+	s.acc += dt * speed
+	for n := 0; s.acc >= TickDt; n++ {
+		if n == limit {
+			s.acc = 0
+			break
+		}
+		for _, e := range s.entities {
+			e.prev = poseOf(e.SpaceComponent)
+		}
+		for _, t := range s.tickers {
+			t.Tick(TickDt)
+		}
+		s.acc -= TickDt
+		s.ticks++
+	}
+	s.alpha = s.acc / TickDt
+
+	// Draw this frame part of the way between the last two ticks. Entities
+	// that are new since then have nothing to blend from yet.
+	for _, e := range s.entities {
+		e.curr = poseOf(e.SpaceComponent)
+		if !e.tracking {
+			e.prev = e.curr
+			e.tracking = true
+		}
+		e.prev.lerp(e.curr, s.alpha).apply(e.SpaceComponent)
+	}
+}
+
+// FixedCollisionSystem runs engo's CollisionSystem on TickSystem's ticks
+// instead of once per frame, so that walls stop the player at the same place
+// at any frame rate.
+type FixedCollisionSystem struct {
+	*common.CollisionSystem
+}
+
+// Update does nothing; collisions are resolved in Tick.
+func (s *FixedCollisionSystem) Update(dt float32) {}
+
+func (s *FixedCollisionSystem) Tick(dt float32) { s.CollisionSystem.Update(dt) }
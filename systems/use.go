@@ -0,0 +1,115 @@
+package systems
+
+import (
+	"github.com/EngoEngine/ecs"
+	"github.com/EngoEngine/engo"
+	"github.com/EngoEngine/engo/common"
+	"github.com/EngoEngine/engo/math"
+
+	"github.com/SkeleboyStudios/SkeleDoom/shaders"
+)
+
+// useRange is how far in front of the player the use button reaches, in
+// world units.
+const useRange float32 = 40
+
+// UseMessageType is the engo.Mailbox type of UseMessage.
+const UseMessageType = "UseMessage"
+
+// UseMessage is dispatched by UseSystem when the player uses while facing a
+// wall within useRange. Systems that own usable walls (doors,
+// switches, ...) listen for it and compare Target against their entities.
+type UseMessage struct {
+	// Target is the wall entity that was used.
+	Target *ecs.BasicEntity
+	// Inventory belongs to the player who pressed use, for key checks. It is
+	// nil when the player has no InventoryComponent.
+	Inventory *InventoryComponent
+}
+
+func (UseMessage) Type() string { return UseMessageType }
+
+type useWallEntity struct {
+	*ecs.BasicEntity
+	*WallMapComponent
+}
+
+// UseSystem turns the player's Use command into a UseMessage for the nearest
+// solid wall directly in front of them. Walls block use of anything
+// behind them, so it accepts every WallMapAble entity.
+//
+//	var useplayerable *systems.ViewPlayerAble
+//	var usewallable   *systems.WallMapAble
+//	w.AddSystemInterface(&systems.UseSystem{}, []any{useplayerable, usewallable}, nil)
+type UseSystem struct {
+	playerID  uint64
+	player    *common.SpaceComponent
+	input     *InputComponent
+	inventory *InventoryComponent
+	walls     []useWallEntity
+}
+
+func (s *UseSystem) AddByInterface(i ecs.Identifier) {
+	if o, ok := i.(ViewPlayerAble); ok {
+		s.playerID = o.GetBasicEntity().ID()
+		s.player = o.GetSpaceComponent()
+		if in, ok := i.(InputFace); ok {
+			s.input = in.GetInputComponent()
+		}
+		if inv, ok := i.(InventoryFace); ok {
+			s.inventory = inv.GetInventoryComponent()
+		}
+		return
+	}
+	// MapSystem's minimap entities embed a nil WallMapComponent.
+	if o, ok := i.(WallMapAble); ok && o.GetWallMapComponent() != nil {
+		s.walls = append(s.walls, useWallEntity{o.GetBasicEntity(), o.GetWallMapComponent()})
+	}
+}
+
+func (s *UseSystem) Remove(basic ecs.BasicEntity) {
+	if s.player != nil && s.playerID == basic.ID() {
+		s.player = nil
+		s.input = nil
+		s.inventory = nil
+		return
+	}
+	for i, wall := range s.walls {
+		if wall.BasicEntity.ID() == basic.ID() {
+			s.walls = append(s.walls[:i], s.walls[i+1:]...)
+			return
+		}
+	}
+}
+
+// Update does nothing; the use button is handled in Tick.
+func (s *UseSystem) Update(dt float32) {}
+
+func (s *UseSystem) Tick(dt float32) {
+	if s.player == nil || s.input == nil || !s.input.Command.Use {
+		return
+	}
+
+	po := shaders.PlayerOffset
+	from := engo.Point{X: s.player.Position.X - po.X, Y: s.player.Position.Y - po.Y}
+	sin, cos := math.Sincos(s.player.Rotation * math.Pi / 180)
+	reach := engo.Line{P1: from, P2: engo.Point{X: from.X + useRange*sin, Y: from.Y - useRange*cos}}
+
+	var target *ecs.BasicEntity
+	var best float32
+	for _, wall := range s.walls {
+		if wall.Passable {
+			continue
+		}
+		p, ok := engo.LineIntersection(reach, wall.Wall)
+		if !ok {
+			continue
+		}
+		if d := from.PointDistanceSquared(p); target == nil || d < best {
+			target, best = wall.BasicEntity, d
+		}
+	}
+	if target != nil {
+		engo.Mailbox.Dispatch(UseMessage{Target: target, Inventory: s.inventory})
+	}
+}
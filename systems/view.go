@@ -52,6 +52,8 @@ type viewPlayerEntity struct {
 
 		weaponSpritesheet *common.Spritesheet
 	}
+	melee    *MeleeComponent // nil if the player can't melee
+	swinging bool            // whether the Hands show a melee swing
 
 	*common.SpaceComponent
 	*common.AnimationComponent
@@ -62,10 +64,34 @@ type viewPlayerEntity struct {
 	*NotViewComponent
 }
 
+const defaultWallHeight float32 = 60
+
+// handsPos is where the Hands rest on screen. A melee swing sweeps them
+// handsSwingX to the left and handsSwingY down, turning them by
+// handsSwingTurn degrees, and back.
+var handsPos = engo.Point{X: 400, Y: 106}
+
+const (
+	handsSwingX    float32 = 180
+	handsSwingY    float32 = 40
+	handsSwingTurn float32 = -30
+)
+
 type ViewWallComponent struct {
 	// Tex is the optional wall texture used by the 3D view shader.
 	// Set this before adding the entity to the world; nil falls back to solid colour.
 	Tex *gl.Texture
+	// Height is the wall's height in world units. It is initialised to
+	// defaultWallHeight by ViewSystem when zero, and may be changed at runtime
+	// (e.g. by a crusher); a wall with no height is not drawn.
+	Height float32
+	// Dark draws the wall dimmed, as if its light were switched off. Tagged
+	// walls are toggled by ActionLight.
+	Dark bool
+	// Offset moves the wall down from the top of a standard wall by that
+	// many world units. A wall with Offset defaultWallHeight-Height stands
+	// on the floor; ElevatorSystem uses this for the edges of raised floors.
+	Offset float32
 }
 
 func (c *ViewWallComponent) GetViewWallComponent() *ViewWallComponent { return c }
@@ -95,6 +121,9 @@ type viewWallEntity struct {
 	*WallMapComponent
 	*NotMapComponent
 	*NotViewComponent
+
+	lit color.RGBA // tint when the wall is not Dark
+	tag string
 }
 
 type ViewSystem struct {
@@ -109,6 +138,18 @@ func (s *ViewSystem) New(w *ecs.World) {
 	s.w = w
 	s.numLines = 60
 	s.lineLength = 1000
+
+	engo.Mailbox.Listen(ActionMessageType, func(msg engo.Message) {
+		m, ok := msg.(ActionMessage)
+		if !ok || m.Kind != ActionLight {
+			return
+		}
+		for _, wall := range s.walls {
+			if wall.tag != "" && wall.tag == m.Target {
+				wall.Dark = !wall.Dark
+			}
+		}
+	})
 }
 
 func (s *ViewSystem) AddByInterface(i ecs.Identifier) {
@@ -139,6 +180,7 @@ func (s *ViewSystem) AddByInterface(i ecs.Identifier) {
 			s.player.Hands.AnimationComponent.AddAnimation(&common.Animation{Name: "shoot", Frames: []int{1, 2}})
 			s.player.Hands.AnimationComponent.AddAnimation(&common.Animation{Name: "busy", Frames: []int{3}})
 			s.player.Hands.AnimationComponent.AddAnimation(&common.Animation{Name: "reload", Frames: []int{4, 5, 6, 7}})
+			s.player.Hands.AnimationComponent.AddAnimation(&common.Animation{Name: "swing", Frames: []int{3}, Loop: true})
 			s.player.Hands.SelectAnimationByName("idle")
 
 			s.player.Hands.BasicEntity = ecs.NewBasic()
@@ -146,7 +188,7 @@ func (s *ViewSystem) AddByInterface(i ecs.Identifier) {
 				Drawable: s.player.Hands.weaponSpritesheet.Cell(0), // Start with idle frame
 			}
 			s.player.Hands.Scale = engo.Point{X: 8, Y: 8}
-			s.player.Hands.Position = engo.Point{X: 400, Y: 106}
+			s.player.Hands.Position = handsPos
 			s.player.Hands.SetShader(common.HUDShader)
 			s.player.Hands.Hidden = false
 			s.player.AnimationComponent = &s.player.Hands.AnimationComponent
@@ -154,6 +196,9 @@ func (s *ViewSystem) AddByInterface(i ecs.Identifier) {
 		} else {
 			println("Hands will not be displayed due to missing texture")
 		}
+		if m, ok := i.(MeleeFace); ok {
+			s.player.melee = m.GetMeleeComponent()
+		}
 		s.player.SpaceComponent = o.GetSpaceComponent()
 		shaders.ViewShader.AddPlayer(o.GetSpaceComponent())
 	}
@@ -161,30 +206,79 @@ func (s *ViewSystem) AddByInterface(i ecs.Identifier) {
 		wa := o.GetWallMapComponent().Wall
 		wall := viewWallEntity{BasicEntity: o.GetBasicEntity()}
 		wall.wall.BasicEntity = ecs.NewBasic()
-		wall.wall.SpaceComponent = common.SpaceComponent{Position: wa.P1, Width: wa.Magnitude(), Height: 60}
-		wallTex := o.GetViewWallComponent().Tex
+		vw := o.GetViewWallComponent()
+		if vw.Height == 0 {
+			vw.Height = defaultWallHeight
+		}
+		wall.wall.SpaceComponent = common.SpaceComponent{Position: wa.P1, Width: wa.Magnitude(), Height: vw.Height}
+		wallTex := vw.Tex
 		wallColor := color.RGBA{0xff, 0xff, 0xff, 0xff} // white so textures render true-colour
 		if wallTex == nil {
 			wallColor = color.RGBA{0x00, 0x00, 0xff, 0xff} // fall back to blue when untextured
 		}
 		wall.wall.RenderComponent = &common.RenderComponent{
-			Drawable: shaders.Wall{Line: wa, H: 60, Tex: wallTex},
+			Drawable: shaders.Wall{Line: wa, H: vw.Height, Z: vw.Offset, Tex: wallTex},
 			Color:    wallColor,
 		}
 		wall.wall.SetShader(shaders.ViewShader)
+		wall.ViewWallComponent = vw
 		wall.WallMapComponent = o.GetWallMapComponent()
+		wall.lit = wallColor
+		wall.tag = tagOf(i)
 		s.w.AddEntity(&wall.wall)
 		s.walls = append(s.walls, wall)
 	}
 }
 
-func (s *ViewSystem) Remove(basic ecs.BasicEntity) {}
+func (s *ViewSystem) Remove(basic ecs.BasicEntity) {
+	if s.player.BasicEntity != nil && s.player.ID() == basic.ID() {
+		// The Hands sprite is only spawned when the weapon sheet loaded.
+		if s.player.AnimationComponent != nil {
+			despawn(s.w, s.player.Hands.BasicEntity, &s.player.Hands.RenderComponent)
+		}
+		shaders.ViewShader.RemovePlayer()
+		s.player = viewPlayerEntity{}
+		return
+	}
+	for i, wall := range s.walls {
+		if wall.BasicEntity.ID() == basic.ID() {
+			despawn(s.w, wall.wall.BasicEntity, wall.wall.RenderComponent)
+			s.walls = append(s.walls[:i], s.walls[i+1:]...)
+			return
+		}
+	}
+}
+
+// swingHands plays the player's melee swing on the Hands: the "swing"
+// animation while the Hands sweep across the screen and back.
+func (s *ViewSystem) swingHands() {
+	if s.player.melee == nil || s.player.Hands.weaponSpritesheet == nil {
+		return
+	}
+	t, swinging := s.player.melee.Swing()
+	if swinging != s.player.swinging {
+		s.player.swinging = swinging
+		if swinging {
+			s.player.Hands.SelectAnimationByName("swing")
+		} else {
+			s.player.Hands.SelectAnimationByName("idle")
+		}
+	}
+	arc := math.Sin(t * math.Pi)
+	s.player.Hands.Position = engo.Point{
+		X: handsPos.X - handsSwingX*arc,
+		Y: handsPos.Y + handsSwingY*arc,
+	}
+	s.player.Hands.Rotation = handsSwingTurn * arc
+}
 
 func (s *ViewSystem) Update(dt float32) {
 	if s.player.SpaceComponent == nil {
 		return
 	}
 
+	s.swingHands()
+
 	const near float32 = 1.0
 	const fovAngleDeg float32 = 90.0
 	tanHalfFov := math.Tan((fovAngleDeg * math.Pi / 180) * 0.5)
@@ -199,6 +293,21 @@ func (s *ViewSystem) Update(dt float32) {
 		e := &s.walls[i]
 		wa := e.WallMapComponent.Wall
 
+		// Rebuild the quad from the live geometry so that walls which were
+		// moved, rotated or resized this frame render in their new place.
+		e.wall.Position = wa.P1
+		e.wall.Width = wa.Magnitude()
+		e.wall.Height = e.Height
+		e.wall.Drawable = shaders.Wall{Line: wa, H: e.Height, Z: e.Offset, Tex: e.Tex}
+		e.wall.Color = e.lit
+		if e.Dark {
+			e.wall.Color = color.RGBA{e.lit.R / 3, e.lit.G / 3, e.lit.B / 3, e.lit.A}
+		}
+		if e.Height <= 0 {
+			e.wall.Hidden = true
+			continue
+		}
+
 		// Translate wall endpoints into player-relative coordinates
 		p1X := wa.P1.X - (playerPos.X - playerOffset.X)
 		p1Y := -wa.P1.Y + (playerPos.Y - playerOffset.Y)
@@ -0,0 +1,373 @@
+package systems
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image/color"
+
+	"github.com/EngoEngine/ecs"
+	"github.com/EngoEngine/engo"
+	"github.com/EngoEngine/engo/common"
+	"github.com/EngoEngine/engo/math"
+)
+
+const (
+	// Defaults for unset WavesDef fields.
+	waveIntermission float32 = 8    // seconds between waves
+	waveGrowth       float32 = 1.25 // how much harder each wave past the list gets
+	waveInterval     float32 = 1.5  // seconds between spawns
+
+	// Score for each kill and each wave cleared, times the wave's number.
+	killPoints int = 10
+	wavePoints int = 100
+
+	// waveHUDMargin is the gap between the wave counter and the top-right
+	// corner of the screen.
+	waveHUDMargin float32 = 10
+)
+
+// SpawnerComponent makes an entity a place survival waves send enemies
+// from. The entity's SpaceComponent Position, in wall world-space, and
+// Rotation are where the enemies appear and which way they face.
+type SpawnerComponent struct {
+	// Name is what WaveDefs pick spawners by.
+	Name string
+}
+
+func (c *SpawnerComponent) GetSpawnerComponent() *SpawnerComponent { return c }
+
+// SpawnerFace is satisfied by anything that embeds *SpawnerComponent.
+type SpawnerFace interface {
+	GetSpawnerComponent() *SpawnerComponent
+}
+
+// SpawnerAble is the interface AddByInterface uses to detect spawners.
+type SpawnerAble interface {
+	common.BasicFace
+	common.SpaceFace
+	SpawnerFace
+}
+
+// WaveDef is one wave of a survival level.
+type WaveDef struct {
+	// Count is how many enemies the wave sends.
+	Count int
+	// Interval is the seconds between spawns. Zero means waveInterval.
+	Interval float32
+	// Spawners names the spawners the wave uses, in turn. Empty uses them
+	// all.
+	Spawners []string
+	// Health, Speed and Damage scale the level's enemy. Zero leaves it as
+	// it is.
+	Health, Speed, Damage float32
+}
+
+// WavesDef is how survival mode plays on a level: the waves to fight, in
+// order, and how they keep getting harder once the list runs out.
+type WavesDef struct {
+	// Intermission is the seconds before each wave.
+	Intermission float32
+	// Growth scales the Count, Health and Damage of the last wave for each
+	// wave played past the end of Waves.
+	Growth float32
+	Waves  []WaveDef
+}
+
+// ParseWaves reads a level's WavesDef from JSON, filling in defaults for
+// anything left out.
+func ParseWaves(b []byte) (WavesDef, error) {
+	var d WavesDef
+	if err := json.Unmarshal(b, &d); err != nil {
+		return d, err
+	}
+	if len(d.Waves) == 0 {
+		return d, errors.New("no waves defined")
+	}
+	for i, w := range d.Waves {
+		if w.Count <= 0 {
+			return d, fmt.Errorf("wave %d: Count must be positive", i+1)
+		}
+		if w.Interval == 0 {
+			d.Waves[i].Interval = waveInterval
+		}
+	}
+	if d.Intermission == 0 {
+		d.Intermission = waveIntermission
+	}
+	if d.Growth == 0 {
+		d.Growth = waveGrowth
+	}
+	return d, nil
+}
+
+// Wave returns wave n, counting from 1.
+func (d WavesDef) Wave(n int) WaveDef {
+	if n <= len(d.Waves) {
+		return d.Waves[n-1]
+	}
+	w := d.Waves[len(d.Waves)-1]
+	g := math.Pow(d.Growth, float32(n-len(d.Waves)))
+	w.Count = int(float32(w.Count)*g + 0.5)
+	w.Health = scale(w.Health) * g
+	w.Damage = scale(w.Damage) * g
+	return w
+}
+
+// scale returns a WaveDef multiplier, treating zero as no change.
+func scale(m float32) float32 {
+	if m == 0 {
+		return 1
+	}
+	return m
+}
+
+// wavePhase is where a survival game is up to.
+type wavePhase int
+
+const (
+	waveBreak    wavePhase = iota // counting down to the next wave
+	waveSpawning                  // sending the wave's enemies
+	waveFighting                  // all sent; waiting for them to die
+	waveOver                      // the player is dead
+)
+
+// spawnerEntity is the system's internal representation of one spawner.
+type spawnerEntity struct {
+	*ecs.BasicEntity
+	*common.SpaceComponent
+	*SpawnerComponent
+}
+
+// WaveSystem runs survival mode: after an intermission it sends each wave of
+// Def's enemies from the spawners, one at a time, and once they are all dead
+// it starts the next intermission. Waves get harder as they go. Kills and
+// cleared waves score points, more in later waves, and when the player dies
+// the game is over and the final score stays on screen. The wave, enemies
+// left, intermission countdown and score are shown at the top right.
+//
+//	waves := &systems.WaveSystem{Def: def, Enemies: enemies}
+//	var waveplayerable  *systems.ViewPlayerAble
+//	var waveenemyable   *systems.EnemyAble
+//	var wavespawnerable *systems.SpawnerAble
+//	w.AddSystemInterface(waves, []any{waveplayerable, waveenemyable, wavespawnerable}, nil)
+type WaveSystem struct {
+	// Def is the level's waves.
+	Def WavesDef
+	// Enemies spawns the waves' enemies, from its Spawn template.
+	Enemies *EnemySystem
+
+	playerID uint64
+	player   *ControlComponent
+	spawners []spawnerEntity
+	alive    map[uint64]bool // the enemies in the level
+
+	wave  int // the current wave, counting from 1; 0 before the first
+	phase wavePhase
+	timer float32 // seconds until the next wave, or the next spawn
+	left  int     // enemies still to send this wave
+	next  int     // which of the wave's spawners sends the next enemy
+	score int
+	kills int
+
+	font   *common.Font
+	status sprite // wave counter at the top right
+	shown  string // status's text
+}
+
+func (s *WaveSystem) New(w *ecs.World) {
+	s.alive = make(map[uint64]bool)
+	s.timer = s.Def.Intermission
+
+	s.font = newHUDFont(12, color.White)
+	s.status = sprite{BasicEntity: ecs.NewBasic()}
+	s.status.RenderComponent = common.RenderComponent{
+		Drawable:    common.Text{Font: s.font},
+		StartZIndex: 60,
+	}
+	s.status.SetShader(common.HUDShader)
+	if s.font != nil {
+		w.AddEntity(&s.status)
+	}
+
+	engo.Mailbox.Listen(EnemyKilledMessageType, func(engo.Message) {
+		if s.phase != waveOver {
+			s.kills++
+			s.score += killPoints * s.wave
+		}
+	})
+}
+
+func (s *WaveSystem) AddByInterface(i ecs.Identifier) {
+	if o, ok := i.(ViewPlayerAble); ok {
+		s.playerID = o.GetBasicEntity().ID()
+		s.player = o.GetControlComponent()
+		return
+	}
+	if o, ok := i.(SpawnerAble); ok {
+		s.spawners = append(s.spawners, spawnerEntity{o.GetBasicEntity(), o.GetSpaceComponent(), o.GetSpawnerComponent()})
+		return
+	}
+	if o, ok := i.(EnemyAble); ok {
+		s.alive[o.GetBasicEntity().ID()] = true
+	}
+}
+
+func (s *WaveSystem) Remove(basic ecs.BasicEntity) {
+	if s.player != nil && s.playerID == basic.ID() {
+		s.player = nil
+		return
+	}
+	delete(s.alive, basic.ID())
+	for i, sp := range s.spawners {
+		if sp.ID() == basic.ID() {
+			s.spawners = append(s.spawners[:i], s.spawners[i+1:]...)
+			return
+		}
+	}
+}
+
+// Tick counts down to the next wave, sends it, and notices when it has been
+// beaten, or when the player has.
+func (s *WaveSystem) Tick(dt float32) {
+	if s.phase == waveOver || len(s.Def.Waves) == 0 {
+		return
+	}
+	if s.player != nil && s.player.Health <= 0 {
+		s.phase = waveOver
+		engo.Mailbox.Dispatch(HUDMessage{Text: fmt.Sprintf("Game over! Final score: %d", s.score)})
+		return
+	}
+
+	switch s.phase {
+	case waveBreak:
+		s.timer -= dt
+		if s.timer > 0 {
+			return
+		}
+		s.wave++
+		s.phase = waveSpawning
+		s.left = s.Def.Wave(s.wave).Count
+		s.next = 0
+		engo.Mailbox.Dispatch(HUDMessage{Text: fmt.Sprintf("Wave %d", s.wave)})
+	case waveSpawning:
+		s.timer -= dt
+		if s.timer > 0 {
+			return
+		}
+		def := s.Def.Wave(s.wave)
+		s.spawn(def)
+		s.left--
+		s.timer = def.Interval
+		if s.left <= 0 {
+			s.phase = waveFighting
+		}
+	case waveFighting:
+		if len(s.alive) > 0 {
+			return
+		}
+		s.score += wavePoints * s.wave
+		s.phase = waveBreak
+		s.timer = s.Def.Intermission
+		engo.Mailbox.Dispatch(HUDMessage{Text: fmt.Sprintf("Wave %d cleared!", s.wave)})
+	}
+}
+
+// spawn sends one of def's enemies from the next of its spawners, already
+// hunting for the player.
+func (s *WaveSystem) spawn(def WaveDef) {
+	var from []spawnerEntity
+	for _, sp := range s.spawners {
+		if len(def.Spawners) == 0 || containsString(def.Spawners, sp.Name) {
+			from = append(from, sp)
+		}
+	}
+	if len(from) == 0 || s.Enemies == nil {
+		println("Warning: wave", s.wave, "has no spawners to send enemies from")
+		return
+	}
+	sp := from[s.next%len(from)]
+	s.next++
+
+	ec := s.Enemies.Spawn
+	ec.Health = scaled(ec.Health, enemyHealth, def.Health)
+	ec.Speed = scaled(ec.Speed, enemySpeed, def.Speed)
+	ec.AttackDamage = scaled(ec.AttackDamage, enemyAttackDamage, def.Damage)
+	ec.MeleeDamage = scaled(ec.MeleeDamage, enemyMeleeDamage, def.Damage)
+	s.Enemies.spawn(sp.Position, sp.Rotation, s.Enemies.alerted(ec))
+}
+
+// scaled returns v, or fallback if v is unset, scaled by the WaveDef
+// multiplier m.
+func scaled(v, fallback, m float32) float32 {
+	if v == 0 {
+		v = fallback
+	}
+	return v * scale(m)
+}
+
+// Update shows the wave counter.
+func (s *WaveSystem) Update(dt float32) {
+	if s.font == nil {
+		return
+	}
+	var text string
+	switch s.phase {
+	case waveBreak:
+		text = fmt.Sprintf("Wave %d in %d   Score %d", s.wave+1, int(math.Ceil(s.timer)), s.score)
+	case waveOver:
+		text = fmt.Sprintf("Game over: wave %d, %d kills   Final score %d", s.wave, s.kills, s.score)
+	default:
+		text = fmt.Sprintf("Wave %d   Enemies %d   Score %d", s.wave, len(s.alive)+s.left, s.score)
+	}
+	if text == s.shown {
+		return
+	}
+	s.shown = text
+	width, _, _ := s.font.TextDimensions(text)
+	s.status.Drawable = common.Text{Font: s.font, Text: text}
+	s.status.Position = engo.Point{X: engo.GameWidth() - float32(width) - waveHUDMargin, Y: waveHUDMargin}
+}
+
+// WaveSave is the saved state of a survival game.
+type WaveSave struct {
+	Wave  int
+	Phase int
+	Timer float32
+	Left  int
+	Next  int
+	Score int
+	Kills int
+}
+
+func (s *WaveSystem) save() *WaveSave {
+	return &WaveSave{
+		Wave:  s.wave,
+		Phase: int(s.phase),
+		Timer: s.timer,
+		Left:  s.left,
+		Next:  s.next,
+		Score: s.score,
+		Kills: s.kills,
+	}
+}
+
+func (s *WaveSystem) restore(g *WaveSave) {
+	s.wave = g.Wave
+	s.phase = wavePhase(g.Phase)
+	s.timer = g.Timer
+	s.left = g.Left
+	s.next = g.Next
+	s.score = g.Score
+	s.kills = g.Kills
+}
+
+// containsString reports whether list holds s.
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}